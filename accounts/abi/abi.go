@@ -17,6 +17,7 @@
 package abi
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -385,6 +386,37 @@ func (abi ABI) Unpack(v interface{}, name string, output []byte) error {
 	return nil
 }
 
+// MethodById looks up the method whose 4 byte id prefixes the given call data.
+func (abi ABI) MethodById(data []byte) (*Method, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("abi: call data too short for method id: %d bytes", len(data))
+	}
+	for _, method := range abi.Methods {
+		if bytes.Equal(method.Id(), data[:4]) {
+			return &method, nil
+		}
+	}
+	return nil, fmt.Errorf("abi: no method with id %#x", data[:4])
+}
+
+// UnpackInput decodes the arguments of a call to the named method. The input
+// is the call data without the leading 4 byte method id.
+func (abi ABI) UnpackInput(name string, input []byte) ([]interface{}, error) {
+	method, exist := abi.Methods[name]
+	if !exist {
+		return nil, fmt.Errorf("abi: method '%s' not found", name)
+	}
+	args := make([]interface{}, len(method.Inputs))
+	for i, arg := range method.Inputs {
+		value, err := toGoType(i, arg, input)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+	return args, nil
+}
+
 func (abi *ABI) UnmarshalJSON(data []byte) error {
 	var fields []struct {
 		Type      string
@@ -1239,3 +1239,71 @@ func TestUnmarshal(t *testing.T) {
 		t.Fatal("expected error:", err)
 	}
 }
+
+func TestMethodById(t *testing.T) {
+	const definition = `[
+	{ "type" : "function", "name" : "transfer", "inputs" : [ { "name" : "to", "type" : "address" }, { "name" : "value", "type" : "uint256" } ] },
+	{ "type" : "function", "name" : "approve", "inputs" : [ { "name" : "spender", "type" : "address" }, { "name" : "value", "type" : "uint256" } ] }]`
+
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, method := range abi.Methods {
+		found, err := abi.MethodById(append(method.Id(), make([]byte, 64)...))
+		if err != nil {
+			t.Fatalf("%s: failed to look up method: %v", name, err)
+		}
+		if found.Name != name {
+			t.Errorf("%s: found method %s", name, found.Name)
+		}
+	}
+	if _, err := abi.MethodById([]byte{0xde, 0xad, 0xbe, 0xef}); err == nil {
+		t.Error("expected error for unknown method id")
+	}
+	if _, err := abi.MethodById([]byte{0xde, 0xad}); err == nil {
+		t.Error("expected error for short call data")
+	}
+}
+
+func TestUnpackInput(t *testing.T) {
+	const definition = `[
+	{ "type" : "function", "name" : "send", "inputs" : [ { "name" : "to", "type" : "address" }, { "name" : "value", "type" : "uint256" }, { "name" : "memo", "type" : "bytes" } ] }]`
+
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, value, memo := common.Address{1}, big.NewInt(1000), []byte("hello")
+
+	data, err := abi.Pack("send", to, value, memo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	method, err := abi.MethodById(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, err := abi.UnpackInput(method.Name, data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 3 {
+		t.Fatalf("expected 3 arguments, got %d", len(args))
+	}
+	if args[0].(common.Address) != to {
+		t.Errorf("address mismatch: have %x, want %x", args[0], to)
+	}
+	if args[1].(*big.Int).Cmp(value) != 0 {
+		t.Errorf("value mismatch: have %v, want %v", args[1], value)
+	}
+	if !bytes.Equal(args[2].([]byte), memo) {
+		t.Errorf("memo mismatch: have %q, want %q", args[2], memo)
+	}
+	if _, err := abi.UnpackInput("send", data[4:36]); err == nil {
+		t.Error("expected error for truncated input")
+	}
+	if _, err := abi.UnpackInput("missing", data[4:]); err == nil {
+		t.Error("expected error for unknown method")
+	}
+}
@@ -321,7 +321,9 @@ func (am *Manager) importKey(key *Key, passphrase string) (Account, error) {
 	return a, nil
 }
 
-// Update changes the passphrase of an existing account.
+// Update changes the passphrase of an existing account. The key is re-encrypted
+// with the manager's scrypt parameters, so updating with an unchanged passphrase
+// migrates a key file to the currently configured KDF strength.
 func (am *Manager) Update(a Account, passphrase, newPassphrase string) error {
 	a, key, err := am.getDecryptedKey(a, passphrase)
 	if err != nil {
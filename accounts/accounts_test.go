@@ -17,6 +17,7 @@
 package accounts
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"runtime"
@@ -109,6 +110,42 @@ func TestSignWithPassphrase(t *testing.T) {
 	}
 }
 
+func TestUpdateReencryptsWithNewParams(t *testing.T) {
+	dir, am := tmpManager(t, true)
+	defer os.RemoveAll(dir)
+
+	pass := "foo"
+	a, err := am.NewAccount(pass)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Reopen the key directory with stronger parameters and migrate the key
+	strong := NewManager(dir, 2*veryLightScryptN, veryLightScryptP)
+	if err := strong.Update(a, pass, pass); err != nil {
+		t.Fatal(err)
+	}
+	keyjson, err := ioutil.ReadFile(a.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored struct {
+		Crypto struct {
+			KDFParams struct {
+				N int `json:"n"`
+			} `json:"kdfparams"`
+		} `json:"crypto"`
+	}
+	if err := json.Unmarshal(keyjson, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored.Crypto.KDFParams.N != 2*veryLightScryptN {
+		t.Errorf("scrypt N mismatch: have %d, want %d", stored.Crypto.KDFParams.N, 2*veryLightScryptN)
+	}
+	if _, err := am.SignWithPassphrase(a, pass, testSigData); err != nil {
+		t.Fatalf("failed to sign with migrated key: %v", err)
+	}
+}
+
 func TestTimedUnlock(t *testing.T) {
 	dir, am := tmpManager(t, true)
 	defer os.RemoveAll(dir)
@@ -92,7 +92,11 @@ func init() {
 		utils.LightModeFlag,
 		utils.LightServFlag,
 		utils.LightPeersFlag,
+		utils.LightServCapsFlag,
 		utils.LightKDFFlag,
+		utils.LightPruneFlag,
+		utils.UltraLightServersFlag,
+		utils.UltraLightFractionFlag,
 		utils.CacheFlag,
 		utils.TrieCacheGenFlag,
 		utils.JSpathFlag,
@@ -74,7 +74,11 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.LightModeFlag,
 			utils.LightServFlag,
 			utils.LightPeersFlag,
+			utils.LightServCapsFlag,
 			utils.LightKDFFlag,
+			utils.LightPruneFlag,
+			utils.UltraLightServersFlag,
+			utils.UltraLightFractionFlag,
 		},
 	},
 	{
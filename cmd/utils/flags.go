@@ -154,10 +154,30 @@ var (
 		Usage: "Maximum number of LES client peers",
 		Value: 20,
 	}
+	LightServCapsFlag = cli.StringFlag{
+		Name:  "lightservcaps",
+		Usage: "Comma separated extensions advertised to LES clients (e.g. mailserver)",
+		Value: "",
+	}
 	LightKDFFlag = cli.BoolFlag{
 		Name:  "lightkdf",
 		Usage: "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
 	}
+	LightPruneFlag = cli.Uint64Flag{
+		Name:  "lightprune",
+		Usage: "Number of recent blocks to keep when pruning light client chain data (0 = no pruning)",
+		Value: 0,
+	}
+	UltraLightServersFlag = cli.StringFlag{
+		Name:  "ultralightservers",
+		Usage: "Comma separated enode URLs of trusted LES servers enabling ultra light client mode",
+		Value: "",
+	}
+	UltraLightFractionFlag = cli.IntFlag{
+		Name:  "ultralightfraction",
+		Usage: "Percentage of trusted LES servers that must announce a new head (0 = 75%)",
+		Value: 0,
+	}
 	// Performance tuning settings
 	CacheFlag = cli.IntFlag{
 		Name:  "cache",
@@ -729,6 +749,8 @@ func RegisterEthService(ctx *cli.Context, stack *node.Node, extra []byte) {
 		LightMode:               ctx.GlobalBool(LightModeFlag.Name),
 		LightServ:               ctx.GlobalInt(LightServFlag.Name),
 		LightPeers:              ctx.GlobalInt(LightPeersFlag.Name),
+		LightPruneRetention:     ctx.GlobalUint64(LightPruneFlag.Name),
+		UltraLightFraction:      ctx.GlobalInt(UltraLightFractionFlag.Name),
 		MaxPeers:                ctx.GlobalInt(MaxPeersFlag.Name),
 		DatabaseCache:           ctx.GlobalInt(CacheFlag.Name),
 		DatabaseHandles:         MakeDatabaseHandles(),
@@ -748,6 +770,12 @@ func RegisterEthService(ctx *cli.Context, stack *node.Node, extra []byte) {
 		EnablePreimageRecording: ctx.GlobalBool(VMEnableDebugFlag.Name),
 	}
 
+	if ctx.GlobalIsSet(LightServCapsFlag.Name) {
+		ethConf.LightServCapabilities = strings.Split(ctx.GlobalString(LightServCapsFlag.Name), ",")
+	}
+	if ctx.GlobalIsSet(UltraLightServersFlag.Name) {
+		ethConf.UltraLightServers = strings.Split(ctx.GlobalString(UltraLightServersFlag.Name), ",")
+	}
 	// Override any default configs in dev mode or the test net
 	switch {
 	case ctx.GlobalBool(TestNetFlag.Name):
@@ -69,6 +69,7 @@ var (
 	topic      whisper.TopicType
 	filterID   uint32
 	msPassword string
+	msAllowed  []discover.NodeID
 )
 
 // cmd arguments
@@ -86,6 +87,9 @@ var (
 	argWorkTime  = flag.Uint("work", 5, "work time in seconds")
 	argPoW       = flag.Float64("pow", whisper.MinimumPoW, "PoW for normal messages in float format (e.g. 2.7)")
 	argServerPoW = flag.Float64("mspow", whisper.MinimumPoW, "PoW requirement for Mail Server request")
+	argRateLimit = flag.Uint("msrate", 0, "minimum time between two requests of the same peer in seconds (0 = unlimited)")
+	argRetention = flag.Uint("msretention", 0, "time after which archived messages are removed in hours (0 = keep forever)")
+	argAllowed   = flag.String("mspeers", "", "comma separated node IDs of the peers allowed to request mail (empty = any peer)")
 
 	argIP     = flag.String("ip", "", "IP address and port of this node (e.g. 127.0.0.1:30303)")
 	argSalt   = flag.String("salt", "", "salt (for topic and key derivation)")
@@ -120,6 +124,16 @@ func processArgs() {
 		}
 	}
 
+	if len(*argAllowed) > 0 {
+		for _, id := range strings.Split(*argAllowed, ",") {
+			nid, err := discover.HexID(strings.TrimSpace(id))
+			if err != nil {
+				utils.Fatalf("Failed to parse the allowed peer [%s]: %s", id, err)
+			}
+			msAllowed = append(msAllowed, nid)
+		}
+	}
+
 	if len(*argTopic) > 0 {
 		x, err := hex.DecodeString(*argTopic)
 		if err != nil {
@@ -200,6 +214,11 @@ func initialize() {
 		}
 		shh = whisper.NewWhisper(&mailServer)
 		mailServer.Init(shh, *argDBPath, msPassword, *argServerPoW)
+		mailServer.Configure(mailserver.Config{
+			RateLimit:    time.Duration(*argRateLimit) * time.Second,
+			AllowedPeers: msAllowed,
+			Retention:    time.Duration(*argRetention) * time.Hour,
+		})
 	} else {
 		shh = whisper.NewWhisper(nil)
 	}
@@ -74,6 +74,16 @@ type Config struct {
 	LightPeers int    // Maximum number of LES client peers
 	MaxPeers   int    // Maximum number of global peers
 
+	LightServCapabilities []string // Extra extensions advertised to LES clients (e.g. "mailserver")
+
+	LightCheckpointNumber uint64      // Index of a trusted CHT to sync light client headers from
+	LightCheckpointRoot   common.Hash // Root hash of the trusted CHT (zero uses the built-in one)
+	LightPruneRetention   uint64      // Number of recent blocks kept when pruning light chain data (0 = no pruning)
+	LightTrieCache        int         // Number of trie values cached by the light client (0 = light.TrieValueCacheLimit)
+
+	UltraLightServers  []string // Enode URLs of trusted LES servers enabling ultra light client mode
+	UltraLightFraction int      // Percentage of trusted servers that must announce a new head (0 = 75%)
+
 	SkipBcVersionCheck bool // e.g. blockchain export
 	DatabaseCache      int
 	DatabaseHandles    int
@@ -19,6 +19,7 @@ package ethapi
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -45,8 +46,6 @@ import (
 	"golang.org/x/net/context"
 )
 
-const defaultGas = 90000
-
 // PublicEthereumAPI provides an API to access Ethereum related information.
 // It offers only methods that operate on public data that is freely available to anyone.
 type PublicEthereumAPI struct {
@@ -503,39 +502,55 @@ type CallArgs struct {
 	Data     hexutil.Bytes   `json:"data"`
 }
 
-func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (string, *big.Int, error) {
+const defaultGas = 90000
+
+var (
+	errInsufficientFunds = errors.New("insufficient funds for value transfer")
+	errGasEstimation     = errors.New("gas required exceeds allowance or always failing transaction")
+)
+
+// callSender returns the account a call is executed from, defaulting to the
+// first local account if none was specified.
+func callSender(b Backend, args CallArgs) common.Address {
+	if args.From != (common.Address{}) {
+		return args.From
+	}
+	accounts := b.AccountManager().Accounts()
+	if len(accounts) == 0 {
+		return common.Address{}
+	}
+	return accounts[0].Address
+}
+
+// callGasPrice returns the gas price a call is executed with, defaulting to 50
+// shannon if none was specified.
+func callGasPrice(args CallArgs) *big.Int {
+	if price := args.GasPrice.ToInt(); price.BitLen() > 0 {
+		return price
+	}
+	return new(big.Int).Mul(big.NewInt(50), common.Shannon)
+}
+
+func doCall(ctx context.Context, b Backend, args CallArgs, blockNr rpc.BlockNumber) (string, *big.Int, error) {
 	defer func(start time.Time) { glog.V(logger.Debug).Infof("call took %v", time.Since(start)) }(time.Now())
 
-	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	state, header, err := b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return "0x", common.Big0, err
 	}
 
 	// Set the account address to interact with
-	var addr common.Address
-	if args.From == (common.Address{}) {
-		accounts := s.b.AccountManager().Accounts()
-		if len(accounts) == 0 {
-			addr = common.Address{}
-		} else {
-			addr = accounts[0].Address
-		}
-	} else {
-		addr = args.From
-	}
+	addr := callSender(b, args)
 
 	// Assemble the CALL invocation
-	gas, gasPrice := args.Gas.ToInt(), args.GasPrice.ToInt()
+	gas, gasPrice := args.Gas.ToInt(), callGasPrice(args)
 	if gas.Cmp(common.Big0) == 0 {
 		gas = big.NewInt(50000000)
 	}
-	if gasPrice.Cmp(common.Big0) == 0 {
-		gasPrice = new(big.Int).Mul(big.NewInt(50), common.Shannon)
-	}
 	msg := types.NewMessage(addr, args.To, 0, args.Value.ToInt(), gas, gasPrice, args.Data, false)
 
 	// Execute the call and return
-	vmenv, vmError, err := s.b.GetVMEnv(ctx, msg, state, header)
+	vmenv, vmError, err := b.GetVMEnv(ctx, msg, state, header)
 	if err != nil {
 		return "0x", common.Big0, err
 	}
@@ -553,39 +568,70 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 // Call executes the given transaction on the state for the given block number.
 // It doesn't make and changes in the state/blockchain and is useful to execute and retrieve values.
 func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (string, error) {
-	result, _, err := s.doCall(ctx, args, blockNr)
+	result, _, err := doCall(ctx, s.b, args, blockNr)
 	return result, err
 }
 
 // EstimateGas returns an estimate of the amount of gas needed to execute the given transaction.
 func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (*hexutil.Big, error) {
+	return doEstimateGas(ctx, s.b, args)
+}
+
+func doEstimateGas(ctx context.Context, b Backend, args CallArgs) (*hexutil.Big, error) {
 	// Binary search the gas requirement, as it may be higher than the amount used
 	var lo, hi uint64
 	if (*big.Int)(&args.Gas).BitLen() > 0 {
 		hi = (*big.Int)(&args.Gas).Uint64()
 	} else {
 		// Retrieve the current pending block to act as the gas ceiling
-		block, err := s.b.BlockByNumber(ctx, rpc.PendingBlockNumber)
+		block, err := b.BlockByNumber(ctx, rpc.PendingBlockNumber)
 		if err != nil {
 			return nil, err
 		}
 		hi = block.GasLimit().Uint64()
 	}
+	// Never search above the gas the sender can actually pay for, if a gas price
+	// was given. Without one the call is executed with an unlimited balance.
+	if price := args.GasPrice.ToInt(); price.BitLen() > 0 {
+		state, _, err := b.StateAndHeaderByNumber(ctx, rpc.PendingBlockNumber)
+		if state == nil || err != nil {
+			return nil, err
+		}
+		balance, err := state.GetBalance(ctx, callSender(b, args))
+		if err != nil {
+			return nil, err
+		}
+		available := new(big.Int).Sub(balance, args.Value.ToInt())
+		if available.Sign() < 0 {
+			return nil, errInsufficientFunds
+		}
+		if allowance := available.Div(available, price); allowance.Cmp(new(big.Int).SetUint64(hi)) < 0 {
+			hi = allowance.Uint64()
+		}
+	}
+	// Create a helper to check whether a gas allowance results in an executable transaction
+	executable := func(gas uint64) bool {
+		(*big.Int)(&args.Gas).SetUint64(gas)
+
+		_, used, err := doCall(ctx, b, args, rpc.PendingBlockNumber)
+
+		// If the transaction became invalid or used all the gas (failed), it's not executable
+		return err == nil && used.Cmp((*big.Int)(&args.Gas)) != 0
+	}
 	for lo+1 < hi {
 		// Take a guess at the gas, and check transaction validity
 		mid := (hi + lo) / 2
-		(*big.Int)(&args.Gas).SetUint64(mid)
-
-		_, gas, err := s.doCall(ctx, args, rpc.PendingBlockNumber)
-
-		// If the transaction became invalid or used all the gas (failed), raise the gas limit
-		if err != nil || gas.Cmp((*big.Int)(&args.Gas)) == 0 {
+		if !executable(mid) {
 			lo = mid
 			continue
 		}
-		// Otherwise assume the transaction succeeded, lower the gas limit
+		// Otherwise the transaction succeeded, lower the gas limit
 		hi = mid
 	}
+	// Reject the transaction as invalid if it still fails at the highest allowance
+	if !executable(hi) {
+		return nil, errGasEstimation
+	}
 	return (*hexutil.Big)(new(big.Int).SetUint64(hi)), nil
 }
 
@@ -1023,11 +1069,29 @@ type SendTxArgs struct {
 	Nonce    *hexutil.Uint64 `json:"nonce"`
 }
 
+// UnmarshalJSON decodes the transaction arguments, accepting the call data under
+// either the "data" key or the "input" key used by newer web3 clients.
+func (args *SendTxArgs) UnmarshalJSON(input []byte) error {
+	type sendTxArgs SendTxArgs
+	var dec struct {
+		sendTxArgs
+		Input *hexutil.Bytes `json:"input"`
+	}
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.Input != nil {
+		if dec.Data != nil && !bytes.Equal(dec.Data, *dec.Input) {
+			return errors.New(`both "data" and "input" are set and not equal`)
+		}
+		dec.Data = *dec.Input
+	}
+	*args = SendTxArgs(dec.sendTxArgs)
+	return nil
+}
+
 // prepareSendTxArgs is a helper function that fills in default values for unspecified tx fields.
 func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
-	if args.Gas == nil {
-		args.Gas = (*hexutil.Big)(big.NewInt(defaultGas))
-	}
 	if args.GasPrice == nil {
 		price, err := b.SuggestPrice(ctx)
 		if err != nil {
@@ -1038,6 +1102,24 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 	if args.Value == nil {
 		args.Value = new(hexutil.Big)
 	}
+	if args.Gas == nil {
+		// Estimate the gas requirement against the pending state (retrieved on
+		// demand by light clients) instead of guessing a fixed limit.
+		gas, err := doEstimateGas(ctx, b, CallArgs{
+			From:     args.From,
+			To:       args.To,
+			GasPrice: *args.GasPrice,
+			Value:    *args.Value,
+			Data:     args.Data,
+		})
+		if err != nil {
+			// Fall back to the old default if the transaction cannot be
+			// estimated, leaving the failure to the pool or the chain.
+			glog.V(logger.Debug).Infoln("gas estimation failed:", err)
+			gas = (*hexutil.Big)(big.NewInt(defaultGas))
+		}
+		args.Gas = gas
+	}
 	if args.Nonce == nil {
 		nonce, err := b.GetPoolNonce(ctx, args.From)
 		if err != nil {
@@ -0,0 +1,233 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/net/context"
+)
+
+func TestSendTxArgsUnmarshalData(t *testing.T) {
+	tests := []struct {
+		input string
+		data  []byte
+		fail  bool
+	}{
+		{input: `{}`, data: nil},
+		{input: `{"data":"0x0102"}`, data: []byte{1, 2}},
+		{input: `{"input":"0x0102"}`, data: []byte{1, 2}},
+		{input: `{"data":"0x0102","input":"0x0102"}`, data: []byte{1, 2}},
+		{input: `{"data":"0x0102","input":"0x0103"}`, fail: true},
+		{input: `{"input":"0x01zz"}`, fail: true},
+		{input: `{"data":"0102"}`, fail: true},
+	}
+	for i, tt := range tests {
+		var args SendTxArgs
+		err := json.Unmarshal([]byte(tt.input), &args)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("test %d: expected error, got data %x", i, []byte(args.Data))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+			continue
+		}
+		if !bytes.Equal(args.Data, tt.data) {
+			t.Errorf("test %d: data mismatch: have %x, want %x", i, []byte(args.Data), tt.data)
+		}
+	}
+}
+
+func TestSendTxArgsUnmarshalFields(t *testing.T) {
+	var args SendTxArgs
+	input := `{"from":"0x0000000000000000000000000000000000000001","gas":"0x5208","nonce":"0x2","input":"0xff"}`
+	if err := json.Unmarshal([]byte(input), &args); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if args.From[19] != 1 {
+		t.Errorf("from mismatch: have %x", args.From)
+	}
+	if args.Gas == nil || args.Gas.ToInt().Uint64() != 21000 {
+		t.Errorf("gas mismatch: have %v", args.Gas)
+	}
+	if args.Nonce == nil || uint64(*args.Nonce) != 2 {
+		t.Errorf("nonce mismatch: have %v", args.Nonce)
+	}
+	if args.To != nil || args.GasPrice != nil || args.Value != nil {
+		t.Errorf("unset fields decoded as non-nil: %+v", args)
+	}
+}
+
+// testBackend is a minimal Backend serving calls from a fixed pending state.
+type testBackend struct {
+	Backend // nil, panics on anything not overridden below
+
+	state  *state.StateDB
+	header *types.Header
+}
+
+type testState struct{ *state.StateDB }
+
+func (s testState) GetBalance(ctx context.Context, addr common.Address) (*big.Int, error) {
+	return s.StateDB.GetBalance(addr), nil
+}
+func (s testState) GetCode(ctx context.Context, addr common.Address) ([]byte, error) {
+	return s.StateDB.GetCode(addr), nil
+}
+func (s testState) GetState(ctx context.Context, a common.Address, b common.Hash) (common.Hash, error) {
+	return s.StateDB.GetState(a, b), nil
+}
+func (s testState) GetNonce(ctx context.Context, addr common.Address) (uint64, error) {
+	return s.StateDB.GetNonce(addr), nil
+}
+
+type testHeaderFetcher struct{}
+
+func (testHeaderFetcher) GetHeader(common.Hash, uint64) *types.Header { return nil }
+
+func (b *testBackend) BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Block, error) {
+	return types.NewBlockWithHeader(b.header), nil
+}
+
+func (b *testBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (State, *types.Header, error) {
+	return testState{b.state.Copy()}, b.header, nil
+}
+
+func (b *testBackend) GetVMEnv(ctx context.Context, msg core.Message, st State, header *types.Header) (*vm.EVM, func() error, error) {
+	statedb := st.(testState).StateDB
+	statedb.GetOrNewStateObject(msg.From()).SetBalance(common.MaxBig)
+
+	context := core.NewEVMContext(msg, header, testHeaderFetcher{})
+	return vm.NewEVM(context, statedb, params.TestChainConfig, vm.Config{}), func() error { return nil }, nil
+}
+
+func newTestBackend(t *testing.T) *testBackend {
+	db, _ := ethdb.NewMemDatabase()
+	statedb, err := state.New(common.Hash{}, db)
+	if err != nil {
+		t.Fatalf("failed to create state: %v", err)
+	}
+	return &testBackend{
+		state:  statedb,
+		header: &types.Header{Number: big.NewInt(1), GasLimit: big.NewInt(4712388), Difficulty: big.NewInt(1), Time: big.NewInt(0)},
+	}
+}
+
+// transferGas is the estimate of a plain transfer. The search treats a call using
+// up all its gas as failed, so it ends one above the intrinsic 21000.
+const transferGas = 21001
+
+var (
+	testPoorAddr   = common.HexToAddress("0x1001")
+	testRichAddr   = common.HexToAddress("0x1002")
+	testTargetAddr = common.HexToAddress("0x1003")
+	testThrowAddr  = common.HexToAddress("0x1004")
+	testEmptyAddr  = common.HexToAddress("0x1005")
+	testGasPrice   = new(big.Int).Mul(big.NewInt(20), common.Shannon)
+)
+
+// Tests that gas estimation is capped by the gas the sender can pay for if a gas
+// price is given and that failing transactions are reported instead of being
+// given the full ceiling.
+func TestEstimateGas(t *testing.T) {
+	b := newTestBackend(t)
+	b.state.SetBalance(testPoorAddr, new(big.Int).Mul(big.NewInt(30000), testGasPrice))
+	b.state.SetBalance(testRichAddr, new(big.Int).Mul(big.NewInt(1e9), testGasPrice))
+	b.state.SetCode(testThrowAddr, common.FromHex("0x600056")) // PUSH1 0 JUMP: invalid jump
+
+	tests := []struct {
+		from  common.Address
+		to    common.Address
+		value int64
+		free  bool // Estimate without a gas price
+		gas   uint64
+		fail  bool
+	}{
+		{from: testPoorAddr, to: testTargetAddr, gas: transferGas},                // Low balance transfer
+		{from: testRichAddr, to: testTargetAddr, gas: transferGas},                // Plain transfer
+		{from: testRichAddr, to: testThrowAddr, fail: true},                       // Always failing call
+		{from: testPoorAddr, to: testThrowAddr, fail: true},                       // Always failing call, low balance
+		{from: testPoorAddr, to: testTargetAddr, value: 10000 * 20e9, fail: true}, // Value leaves too little for gas
+		{from: testPoorAddr, to: testTargetAddr, value: 1 << 62, fail: true},      // Value exceeds balance
+		{from: testEmptyAddr, to: testTargetAddr, free: true, gas: transferGas},   // Unfunded sender, no gas price
+		{from: testEmptyAddr, to: testThrowAddr, free: true, fail: true},          // Always failing call, no gas price
+	}
+	for i, tt := range tests {
+		to := tt.to
+		args := CallArgs{From: tt.from, To: &to, GasPrice: hexutil.Big(*testGasPrice), Value: hexutil.Big(*big.NewInt(tt.value))}
+		if tt.free {
+			args.GasPrice = hexutil.Big{}
+		}
+
+		gas, err := doEstimateGas(context.Background(), b, args)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("test %d: expected error, got gas %v", i, gas.ToInt())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+			continue
+		}
+		if gas.ToInt().Uint64() != tt.gas {
+			t.Errorf("test %d: gas mismatch: have %v, want %d", i, gas.ToInt(), tt.gas)
+		}
+	}
+}
+
+// Tests that transactions without a gas limit get an estimated one, falling back
+// to the default limit if the estimation fails.
+func TestSetDefaultsGas(t *testing.T) {
+	b := newTestBackend(t)
+	b.state.SetBalance(testPoorAddr, new(big.Int).Mul(big.NewInt(30000), testGasPrice))
+	b.state.SetCode(testThrowAddr, common.FromHex("0x600056"))
+
+	tests := []struct {
+		to  common.Address
+		gas uint64
+	}{
+		{to: testTargetAddr, gas: transferGas},
+		{to: testThrowAddr, gas: defaultGas},
+	}
+	for i, tt := range tests {
+		to, nonce := tt.to, hexutil.Uint64(0)
+		args := SendTxArgs{From: testPoorAddr, To: &to, GasPrice: (*hexutil.Big)(testGasPrice), Nonce: &nonce}
+		if err := args.setDefaults(context.Background(), b); err != nil {
+			t.Errorf("test %d: failed to set defaults: %v", i, err)
+			continue
+		}
+		if args.Gas.ToInt().Uint64() != tt.gas {
+			t.Errorf("test %d: gas mismatch: have %v, want %d", i, args.Gas.ToInt(), tt.gas)
+		}
+	}
+}
@@ -117,6 +117,27 @@ web3._extend({
 		new web3._extend.Method({
 			name: 'stopWS',
 			call: 'admin_stopWS'
+		}),
+		new web3._extend.Method({
+			name: 'serverScores',
+			call: 'admin_serverScores'
+		}),
+		new web3._extend.Method({
+			// The formatter pads a missing retention with null, so pruneNow()
+			// falls back to the configured retention
+			name: 'pruneNow',
+			call: 'admin_pruneNow',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'setCheckpoint',
+			call: 'admin_setCheckpoint',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'databaseStats',
+			call: 'admin_databaseStats'
 		})
 	],
 	properties:
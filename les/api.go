@@ -0,0 +1,116 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+var (
+	errNoServerPool = errors.New("server pool not running")
+	errNoRetention  = errors.New("no pruning retention specified")
+	errNoDiskStats  = errors.New("chain database is not stored on disk")
+)
+
+// keySpaceLimit is a key sorting after all keys stored in the chain database,
+// used to measure the size of the entire key space.
+var keySpaceLimit = bytes.Repeat([]byte{0xff}, 64)
+
+// PrivateLightServerAPI provides an API to inspect the servers known by a
+// light client over the private admin endpoint.
+type PrivateLightServerAPI struct {
+	pm *ProtocolManager
+}
+
+// NewPrivateLightServerAPI creates a new light server pool API.
+func NewPrivateLightServerAPI(pm *ProtocolManager) *PrivateLightServerAPI {
+	return &PrivateLightServerAPI{pm: pm}
+}
+
+// ServerScores returns the collected statistics of all known light servers,
+// ordered by the score used when selecting a server for an ODR request.
+func (api *PrivateLightServerAPI) ServerScores() ([]ServerScore, error) {
+	if api.pm.serverPool == nil {
+		return nil, errNoServerPool
+	}
+	return api.pm.serverPool.scores(), nil
+}
+
+// PrivateLightChainAPI provides an API to maintain the local light chain data
+// over the private admin endpoint.
+type PrivateLightChainAPI struct {
+	chain     *light.LightChain
+	chainDb   ethdb.Database
+	retention uint64
+}
+
+// NewPrivateLightChainAPI creates a new light chain maintenance API. The given
+// retention is used by PruneNow unless the caller overrides it.
+func NewPrivateLightChainAPI(chain *light.LightChain, chainDb ethdb.Database, retention uint64) *PrivateLightChainAPI {
+	return &PrivateLightChainAPI{chain: chain, chainDb: chainDb, retention: retention}
+}
+
+// DatabaseStats reports the disk usage of the light chain database.
+type DatabaseStats struct {
+	Size  int64  `json:"size"`  // Approximate size of the stored data in bytes
+	Stats string `json:"stats"` // LevelDB per level table statistics
+}
+
+// DatabaseStats returns the disk usage of the light chain database, e.g. to check
+// the effect of PruneNow. Recently pruned data is only released from disk once
+// the database compacts the affected tables.
+func (api *PrivateLightChainAPI) DatabaseStats() (*DatabaseStats, error) {
+	ldb, ok := api.chainDb.(*ethdb.LDBDatabase)
+	if !ok {
+		return nil, errNoDiskStats
+	}
+	sizes, err := ldb.LDB().SizeOf([]util.Range{{Start: nil, Limit: keySpaceLimit}})
+	if err != nil {
+		return nil, err
+	}
+	stats, err := ldb.LDB().GetProperty("leveldb.stats")
+	if err != nil {
+		return nil, err
+	}
+	return &DatabaseStats{Size: sizes.Sum(), Stats: stats}, nil
+}
+
+// PruneNow removes the locally stored data of blocks more than retention blocks
+// behind the chain head, falling back to the configured retention if none is
+// given. It returns the number of pruned blocks.
+func (api *PrivateLightChainAPI) PruneNow(retention *uint64) (int, error) {
+	keep := api.retention
+	if retention != nil {
+		keep = *retention
+	}
+	if keep == 0 {
+		return 0, errNoRetention
+	}
+	return api.chain.Prune(keep), nil
+}
+
+// SetCheckpoint replaces the trusted CHT used to retrieve headers older than the
+// local chain head. Checkpoints older than the currently trusted one are rejected.
+func (api *PrivateLightChainAPI) SetCheckpoint(number uint64, root common.Hash) error {
+	return api.chain.SetTrustedCht(light.TrustedCht{Number: number, Root: root})
+}
@@ -0,0 +1,70 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// Tests that the disk usage of the chain database is reported.
+func TestDatabaseStats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "les-dbstats")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := ethdb.NewLDBDatabase(dir, 16, 16)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	// Random values as the tables are compressed
+	value := make([]byte, 1024)
+	for i := 0; i < 256; i++ {
+		rand.Read(value)
+		if err := db.Put([]byte{byte(i), 'k'}, value); err != nil {
+			t.Fatalf("failed to insert item %d: %v", i, err)
+		}
+	}
+	// Flush the memory table so the data is accounted on disk
+	if err := db.LDB().CompactRange(util.Range{}); err != nil {
+		t.Fatalf("failed to compact database: %v", err)
+	}
+	stats, err := NewPrivateLightChainAPI(nil, db, 0).DatabaseStats()
+	if err != nil {
+		t.Fatalf("failed to retrieve database stats: %v", err)
+	}
+	if stats.Size < 256*1024 {
+		t.Errorf("database size too small: have %d, want at least %d", stats.Size, 256*1024)
+	}
+	if stats.Stats == "" {
+		t.Errorf("missing level statistics")
+	}
+	// In memory databases have no disk usage to report
+	memdb, _ := ethdb.NewMemDatabase()
+	if _, err := NewPrivateLightChainAPI(nil, memdb, 0).DatabaseStats(); err != errNoDiskStats {
+		t.Errorf("error mismatch: have %v, want %v", err, errNoDiskStats)
+	}
+}
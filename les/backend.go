@@ -66,8 +66,9 @@ type LightEthereum struct {
 	solcPath       string
 	solc           *compiler.Solidity
 
-	netVersionId  int
-	netRPCService *ethapi.PublicNetAPI
+	netVersionId   int
+	netRPCService  *ethapi.PublicNetAPI
+	pruneRetention uint64
 }
 
 func New(ctx *node.ServiceContext, config *eth.Config) (*LightEthereum, error) {
@@ -83,7 +84,7 @@ func New(ctx *node.ServiceContext, config *eth.Config) (*LightEthereum, error) {
 		return nil, err
 	}
 
-	odr := NewLesOdr(chainDb)
+	odr := NewLesOdr(chainDb, config.LightTrieCache)
 	relay := NewLesTxRelay()
 	eth := &LightEthereum{
 		odr:            odr,
@@ -95,6 +96,7 @@ func New(ctx *node.ServiceContext, config *eth.Config) (*LightEthereum, error) {
 		shutdownChan:   make(chan bool),
 		netVersionId:   config.NetworkId,
 		solcPath:       config.SolcPath,
+		pruneRetention: config.LightPruneRetention,
 	}
 
 	if config.ChainConfig == nil {
@@ -108,11 +110,27 @@ func New(ctx *node.ServiceContext, config *eth.Config) (*LightEthereum, error) {
 		}
 		return nil, err
 	}
+	if config.LightCheckpointRoot != (common.Hash{}) {
+		cht := light.TrustedCht{Number: config.LightCheckpointNumber, Root: config.LightCheckpointRoot}
+		if err := eth.blockchain.SetTrustedCht(cht); err != nil {
+			glog.V(logger.Warn).Infof("Ignoring configured checkpoint: %v", err)
+		}
+	}
+	if config.LightPruneRetention > 0 {
+		eth.blockchain.StartPruning(config.LightPruneRetention)
+	}
 
 	eth.txPool = light.NewTxPool(eth.chainConfig, eth.eventMux, eth.blockchain, eth.relay)
 	if eth.protocolManager, err = NewProtocolManager(eth.chainConfig, config.LightMode, config.NetworkId, eth.eventMux, eth.pow, eth.blockchain, nil, chainDb, odr, relay); err != nil {
 		return nil, err
 	}
+	if len(config.UltraLightServers) > 0 {
+		if eth.protocolManager.ulc, err = newULC(config.UltraLightServers, config.UltraLightFraction); err != nil {
+			return nil, err
+		}
+		eth.blockchain.SetValidator(ulcValidator{eth.blockchain.Validator()})
+		glog.V(logger.Info).Infof("Ultra light client mode enabled with %d trusted servers", len(eth.protocolManager.ulc.trusted))
+	}
 
 	eth.ApiBackend = &LesApiBackend{eth, nil}
 	eth.ApiBackend.gpo = gasprice.NewLightPriceOracle(eth.ApiBackend)
@@ -165,6 +183,14 @@ func (s *LightEthereum) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   s.netRPCService,
 			Public:    true,
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateLightServerAPI(s.protocolManager),
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateLightChainAPI(s.blockchain, s.chainDb, s.pruneRetention),
 		},
 	}...)
 }
@@ -173,6 +199,13 @@ func (s *LightEthereum) ResetWithGenesisBlock(gb *types.Block) {
 	s.blockchain.ResetWithGenesisBlock(gb)
 }
 
+// PurgeCaches drops the in-memory caches of the light chain and of the trie
+// values retrieved on demand, e.g. to release memory.
+func (s *LightEthereum) PurgeCaches() {
+	s.blockchain.PurgeCaches()
+	s.odr.TrieValueCache().Purge()
+}
+
 func (s *LightEthereum) BlockChain() *light.LightChain      { return s.blockchain }
 func (s *LightEthereum) TxPool() *light.TxPool              { return s.txPool }
 func (s *LightEthereum) LesVersion() int                    { return int(s.protocolManager.SubProtocols[0].Version) }
@@ -191,6 +224,11 @@ func (s *LightEthereum) Start(srvr *p2p.Server) error {
 	glog.V(logger.Info).Infof("WARNING: light client mode is an experimental feature")
 	s.netRPCService = ethapi.NewPublicNetAPI(srvr, s.netVersionId)
 	s.protocolManager.Start(srvr)
+	if ulc := s.protocolManager.ulc; ulc != nil {
+		for _, node := range ulc.trusted {
+			srvr.AddPeer(node)
+		}
+	}
 	return nil
 }
 
@@ -418,7 +418,7 @@ func (f *lightFetcher) nextRequest(reqID uint64) (*peer, *fetcherTreeNode, uint6
 
 	for p, fp := range f.peers {
 		for hash, n := range fp.nodeByHash {
-			if !f.checkKnownNode(p, n) && !n.requested && (bestTd == nil || n.td.Cmp(bestTd) >= 0) {
+			if !f.checkKnownNode(p, n) && !n.requested && (bestTd == nil || n.td.Cmp(bestTd) >= 0) && f.trustedAnnounced(hash) {
 				amount := f.requestAmount(p, n)
 				if bestTd == nil || n.td.Cmp(bestTd) > 0 || amount < bestAmount {
 					bestHash = hash
@@ -437,6 +437,10 @@ func (f *lightFetcher) nextRequest(reqID uint64) (*peer, *fetcherTreeNode, uint6
 		if fp == nil || fp.nodeByHash[bestHash] == nil {
 			return false, 0
 		}
+		if f.pm.ulc != nil && !f.pm.ulc.isTrusted(p.ID()) {
+			// headers are not PoW verified, only download them from trusted servers
+			return false, 0
+		}
 		return true, p.fcServer.CanSend(p.GetRequestCost(GetBlockHeadersMsg, int(bestAmount)))
 	})
 	if !locked {
@@ -449,6 +453,21 @@ func (f *lightFetcher) nextRequest(reqID uint64) (*peer, *fetcherTreeNode, uint6
 	return peer, node, bestAmount, false
 }
 
+// trustedAnnounced tells if a head may be downloaded. In ultra light client mode
+// this requires it to be announced by the configured number of trusted servers.
+func (f *lightFetcher) trustedAnnounced(hash common.Hash) bool {
+	if f.pm.ulc == nil {
+		return true
+	}
+	count := 0
+	for p, fp := range f.peers {
+		if f.pm.ulc.isTrusted(p.ID()) && fp.nodeByHash[hash] != nil {
+			count++
+		}
+	}
+	return count >= f.pm.ulc.minTrusted
+}
+
 // deliverHeaders delivers header download request responses for processing
 func (f *lightFetcher) deliverHeaders(peer *peer, reqID uint64, headers []*types.Header) {
 	f.deliverChn <- fetchResponse{reqID: reqID, headers: headers, peer: peer}
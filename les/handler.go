@@ -58,6 +58,7 @@ const (
 	MaxProofsFetch       = 64  // Amount of merkle proofs to be fetched per retrieval request
 	MaxHeaderProofsFetch = 64  // Amount of merkle proofs to be fetched per retrieval request
 	MaxTxSend            = 64  // Amount of transactions to be send per request
+	MaxTxStatus          = 256 // Amount of transactions to be queried per request
 
 	disableClientRemovePeer = false
 )
@@ -90,6 +91,9 @@ type BlockChain interface {
 type txPool interface {
 	// AddTransactions should add the given transactions to the pool.
 	AddBatch([]*types.Transaction) error
+
+	// Get returns a transaction if it is contained in the pool and nil otherwise.
+	Get(hash common.Hash) *types.Transaction
 }
 
 type ProtocolManager struct {
@@ -103,6 +107,7 @@ type ProtocolManager struct {
 	odr         *LesOdr
 	server      *LesServer
 	serverPool  *serverPool
+	ulc         *ulc // ultra light client configuration, nil if disabled
 
 	downloader *downloader.Downloader
 	fetcher    *lightFetcher
@@ -192,6 +197,7 @@ func NewProtocolManager(chainConfig *params.ChainConfig, lightSync bool, network
 	if len(manager.SubProtocols) == 0 {
 		return nil, errIncompatibleConfig
 	}
+	manager.SubProtocols = append(manager.SubProtocols, manager.txStatusProtocol())
 
 	removePeer := manager.removePeer
 	if disableClientRemovePeer {
@@ -396,7 +402,7 @@ func (pm *ProtocolManager) handle(p *peer) error {
 	}
 }
 
-var reqList = []uint64{GetBlockHeadersMsg, GetBlockBodiesMsg, GetCodeMsg, GetReceiptsMsg, GetProofsMsg, SendTxMsg, GetHeaderProofsMsg}
+var reqList = []uint64{GetBlockHeadersMsg, GetBlockBodiesMsg, GetCodeMsg, GetReceiptsMsg, GetProofsMsg, SendTxMsg, GetHeaderProofsMsg, txStatusCostCode}
 
 // handleMsg is invoked whenever an inbound message is received from a remote
 // peer. The remote connection is torn down upon returning any error.
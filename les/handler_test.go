@@ -19,15 +19,18 @@ package les
 import (
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/light"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
+	"golang.org/x/net/context"
 )
 
 func expectResponse(r p2p.MsgReader, msgcode, reqID, bv uint64, data interface{}) error {
@@ -336,3 +339,113 @@ func testGetProofs(t *testing.T, protocol int) {
 		t.Errorf("proofs mismatch: %v", err)
 	}
 }
+
+// Tests that the status of transactions can be retrieved based on their hashes
+// over the lestx sub-protocol.
+func TestGetTxStatusLes1(t *testing.T) { testGetTxStatus(t, 1) }
+
+func testGetTxStatus(t *testing.T, protocol int) {
+	// Assemble the test environment
+	pm, _, _ := newTestProtocolManagerMust(t, false, 4, testChainGen)
+	bc := pm.blockchain.(*core.BlockChain)
+
+	pool := new(testTxPool)
+	pending := newTestTransaction(testBankKey, 100, 0)
+	pool.AddBatch([]*types.Transaction{pending})
+	pm.txpool = pool
+
+	peer, _ := newTestPeer(t, "peer", protocol, pm, true)
+	defer peer.close()
+	txStatus := peer.startTxStatus(pm)
+	defer txStatus.Close()
+
+	// Collect the hashes to request, and the response to expect
+	hashes, status := []common.Hash{pending.Hash()}, []light.TxStatus{{Status: light.TxStatusPending}}
+	for i := uint64(1); i <= bc.CurrentBlock().NumberU64(); i++ {
+		block := bc.GetBlockByNumber(i)
+		for idx, tx := range block.Transactions() {
+			hashes = append(hashes, tx.Hash())
+			status = append(status, light.TxStatus{
+				Status:      light.TxStatusIncluded,
+				BlockHash:   block.Hash(),
+				BlockNumber: block.NumberU64(),
+				Index:       uint64(idx),
+			})
+		}
+	}
+	hashes = append(hashes, common.Hash{})
+	status = append(status, light.TxStatus{Status: light.TxStatusUnknown})
+
+	// Send the hash request and verify the response
+	cost := peer.GetRequestCost(txStatusCostCode, len(hashes))
+	sendRequest(txStatus, GetTxStatusMsg, 42, cost, hashes)
+	if err := expectResponse(txStatus, TxStatusMsg, 42, testBufLimit, status); err != nil {
+		t.Errorf("transaction status mismatch: %v", err)
+	}
+}
+
+// Tests that light clients retrieve transaction status from servers running the
+// lestx sub-protocol.
+func TestTxStatusOdrLes1(t *testing.T) { testTxStatusOdr(t, 1) }
+
+func testTxStatusOdr(t *testing.T, protocol int) {
+	pm, _, _ := newTestProtocolManagerMust(t, false, 4, testChainGen)
+	lpm, _, odr := newTestProtocolManagerMust(t, true, 0, nil)
+	_, err1, lpeer, err2 := newTestPeerPair("peer", protocol, pm, lpm)
+	pool := &testServerPool{}
+	pool.setPeer(lpeer)
+	odr.serverPool = pool
+	select {
+	case <-time.After(time.Millisecond * 100):
+	case err := <-err1:
+		t.Fatalf("peer 1 handshake error: %v", err)
+	case err := <-err2:
+		t.Fatalf("peer 2 handshake error: %v", err)
+	}
+	// The lestx sub-protocol attaches itself once the les handshake is done
+	for i := 0; !lpeer.ServesTxStatus(); i++ {
+		if i == 20 {
+			t.Fatalf("server does not serve transaction status")
+		}
+		time.Sleep(txStatusPeerWait)
+	}
+	block := pm.blockchain.(*core.BlockChain).GetBlockByNumber(1)
+	hashes := []common.Hash{block.Transactions()[0].Hash(), common.Hash{}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	status, err := light.GetTransactionStatus(ctx, odr, hashes)
+	if err != nil {
+		t.Fatalf("failed to retrieve transaction status: %v", err)
+	}
+	if len(status) != 2 || status[0].Status != light.TxStatusIncluded || status[0].BlockHash != block.Hash() || status[1].Status != light.TxStatusUnknown {
+		t.Errorf("transaction status mismatch: %+v", status)
+	}
+}
+
+// Tests that servers advertise their protocol extensions in the handshake.
+func TestCapabilitiesLes1(t *testing.T) { testCapabilities(t, 1) }
+
+func testCapabilities(t *testing.T, protocol int) {
+	pm, _, _ := newTestProtocolManagerMust(t, false, 0, nil)
+	lpm, _, _ := newTestProtocolManagerMust(t, true, 0, nil)
+	pm.server.capabilities = []string{"mailserver"}
+	expect := []string{CapTxStatus, "mailserver"}
+
+	_, err1, lpeer, err2 := newTestPeerPair("peer", protocol, pm, lpm)
+	select {
+	case <-time.After(time.Millisecond * 100):
+	case err := <-err1:
+		t.Fatalf("peer 1 handshake error: %v", err)
+	case err := <-err2:
+		t.Fatalf("peer 2 handshake error: %v", err)
+	}
+	for _, name := range expect {
+		if !lpeer.HasCapability(name) {
+			t.Errorf("capability %q not advertised", name)
+		}
+	}
+	if len(lpeer.capabilities) != len(expect) {
+		t.Errorf("capability count mismatch: have %d, want %d", len(lpeer.capabilities), len(expect))
+	}
+}
@@ -141,7 +141,7 @@ func newTestProtocolManager(lightSync bool, blocks int, generator func(int, *cor
 	)
 
 	if lightSync {
-		odr = NewLesOdr(db)
+		odr = NewLesOdr(db, 0)
 		chain, _ = light.NewLightChain(odr, chainConfig, pow, evmux)
 	} else {
 		blockchain, _ := core.NewBlockChain(db, chainConfig, pow, evmux, vm.Config{})
@@ -194,7 +194,7 @@ type testTxPool struct {
 
 // AddTransactions appends a batch of transactions to the pool, and notifies any
 // listeners if the addition channel is non nil
-func (p *testTxPool) AddBatch(txs []*types.Transaction) {
+func (p *testTxPool) AddBatch(txs []*types.Transaction) error {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
@@ -202,6 +202,20 @@ func (p *testTxPool) AddBatch(txs []*types.Transaction) {
 	if p.added != nil {
 		p.added <- txs
 	}
+	return nil
+}
+
+// Get returns a transaction if it is contained in the pool and nil otherwise.
+func (p *testTxPool) Get(hash common.Hash) *types.Transaction {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	for _, tx := range p.pool {
+		if tx.Hash() == hash {
+			return tx
+		}
+	}
+	return nil
 }
 
 // GetTransactions returns all the transactions known to the pool
@@ -295,6 +309,11 @@ func newTestPeerPair(name string, version int, pm, pm2 *ProtocolManager) (*peer,
 			errc2 <- p2p.DiscQuitting
 		}
 	}()
+	// Run the lestx sub-protocol next to les
+	txApp, txNet := p2p.MsgPipe()
+	go pm.txStatusProtocol().Run(peer.Peer, txNet)
+	go pm2.txStatusProtocol().Run(peer2.Peer, txApp)
+
 	return peer, errc, peer2, errc2
 }
 
@@ -317,6 +336,7 @@ func (p *testPeer) handshake(t *testing.T, td *big.Int, head common.Hash, headNu
 	expList = expList.add("flowControl/BL", testBufLimit)
 	expList = expList.add("flowControl/MRR", uint64(1))
 	expList = expList.add("flowControl/MRC", testRCL())
+	expList = expList.add("capabilities", []string{CapTxStatus})
 
 	if err := p2p.ExpectMsg(p.app, StatusMsg, expList); err != nil {
 		t.Fatalf("status recv: %v", err)
@@ -331,6 +351,14 @@ func (p *testPeer) handshake(t *testing.T, td *big.Int, head common.Hash, headNu
 	}
 }
 
+// startTxStatus runs the lestx sub-protocol of the peer at the given protocol
+// manager, returning the local side of its connection.
+func (p *testPeer) startTxStatus(pm *ProtocolManager) *p2p.MsgPipeRW {
+	app, net := p2p.MsgPipe()
+	go pm.txStatusProtocol().Run(p.peer.Peer, net)
+	return app
+}
+
 // close terminates the local side of the peer, notifying the remote protocol
 // manager of termination.
 func (p *testPeer) close() {
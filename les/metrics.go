@@ -19,6 +19,7 @@ package les
 import (
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/p2p"
+	gometrics "github.com/rcrowley/go-metrics"
 )
 
 var (
@@ -64,6 +65,55 @@ var (
 	miscOutTrafficMeter = metrics.NewMeter("les/misc/out/traffic")
 )
 
+// odrMetrics collects the client side statistics of a certain ODR request type
+type odrMetrics struct {
+	reqMeter     gometrics.Meter // requests started
+	retryMeter   gometrics.Meter // requests sent to additional servers
+	timeoutMeter gometrics.Meter // servers not answering within the soft timeout
+	invalidMeter gometrics.Meter // servers sending an invalid answer
+	reqTimer     gometrics.Timer // response times of valid answers
+}
+
+func newOdrMetrics(name string) *odrMetrics {
+	return &odrMetrics{
+		reqMeter:     metrics.NewMeter("les/client/" + name + "/req"),
+		retryMeter:   metrics.NewMeter("les/client/" + name + "/retry"),
+		timeoutMeter: metrics.NewMeter("les/client/" + name + "/timeout"),
+		invalidMeter: metrics.NewMeter("les/client/" + name + "/invalid"),
+		reqTimer:     metrics.NewTimer("les/client/" + name + "/time"),
+	}
+}
+
+var (
+	odrBodyMetrics        = newOdrMetrics("bodies")
+	odrReceiptMetrics     = newOdrMetrics("receipts")
+	odrProofMetrics       = newOdrMetrics("proofs")
+	odrCodeMetrics        = newOdrMetrics("code")
+	odrHeaderProofMetrics = newOdrMetrics("headerproofs")
+	odrTxStatusMetrics    = newOdrMetrics("txstatus")
+	odrOtherMetrics       = newOdrMetrics("other")
+)
+
+// requestMetrics returns the metrics collected for the type of the given request
+func requestMetrics(req LesOdrRequest) *odrMetrics {
+	switch req.(type) {
+	case *BlockRequest:
+		return odrBodyMetrics
+	case *ReceiptsRequest:
+		return odrReceiptMetrics
+	case *TrieRequest:
+		return odrProofMetrics
+	case *CodeRequest:
+		return odrCodeMetrics
+	case *ChtRequest:
+		return odrHeaderProofMetrics
+	case *TxStatusRequest:
+		return odrTxStatusMetrics
+	default:
+		return odrOtherMetrics
+	}
+}
+
 // meteredMsgReadWriter is a wrapper around a p2p.MsgReadWriter, capable of
 // accumulating the above defined metrics based on the data stream contents.
 type meteredMsgReadWriter struct {
@@ -27,6 +27,7 @@ import (
 	"github.com/ethereum/go-ethereum/light"
 	"github.com/ethereum/go-ethereum/logger"
 	"github.com/ethereum/go-ethereum/logger/glog"
+	"github.com/hashicorp/golang-lru"
 	"golang.org/x/net/context"
 )
 
@@ -52,13 +53,21 @@ type LesOdr struct {
 	mlock, clock sync.Mutex
 	sentReqs     map[uint64]*sentReq
 	serverPool   odrPeerSelector
+	trieCache    *lru.Cache
 }
 
-func NewLesOdr(db ethdb.Database) *LesOdr {
+// NewLesOdr creates an ODR backend caching up to trieCacheSize recently read trie
+// values. A non-positive size uses light.TrieValueCacheLimit.
+func NewLesOdr(db ethdb.Database, trieCacheSize int) *LesOdr {
+	if trieCacheSize <= 0 {
+		trieCacheSize = light.TrieValueCacheLimit
+	}
+	trieCache, _ := lru.New(trieCacheSize)
 	return &LesOdr{
-		db:       db,
-		stop:     make(chan struct{}),
-		sentReqs: make(map[uint64]*sentReq),
+		db:        db,
+		stop:      make(chan struct{}),
+		sentReqs:  make(map[uint64]*sentReq),
+		trieCache: trieCache,
 	}
 }
 
@@ -70,6 +79,12 @@ func (odr *LesOdr) Database() ethdb.Database {
 	return odr.db
 }
 
+// TrieValueCache returns the cache of recently read trie values (implementation
+// of light.TrieValueCacher)
+func (odr *LesOdr) TrieValueCache() *lru.Cache {
+	return odr.trieCache
+}
+
 // validatorFunc is a function that processes a message and returns true if
 // it was a meaningful answer to a given request
 type validatorFunc func(ethdb.Database, *Msg) bool
@@ -77,17 +92,26 @@ type validatorFunc func(ethdb.Database, *Msg) bool
 // sentReq is a request waiting for an answer that satisfies its valFunc
 type sentReq struct {
 	valFunc  validatorFunc
-	sentTo   map[*peer]chan struct{}
+	metrics  *odrMetrics
+	sentTo   map[*peer]sentReqToPeer
 	lock     sync.RWMutex  // protects acces to sentTo
 	answered chan struct{} // closed and set to nil when any peer answers it
 }
 
+// sentReqToPeer notifies the request-from-peer goroutine (requestPeer) about a response
+// delivered by a given peer. Only one delivery is allowed per request per peer,
+// after which delivered or invalid is closed and the entry is removed from sentTo.
+type sentReqToPeer struct {
+	delivered, invalid chan struct{}
+}
+
 const (
 	MsgBlockBodies = iota
 	MsgCode
 	MsgReceipts
 	MsgProofs
 	MsgHeaderProofs
+	MsgTxStatus
 )
 
 // Msg encodes a LES message that delivers reply data for a request
@@ -99,13 +123,14 @@ type Msg struct {
 
 // Deliver is called by the LES protocol manager to deliver ODR reply messages to waiting requests
 func (self *LesOdr) Deliver(peer *peer, msg *Msg) error {
-	var delivered chan struct{}
+	var chans sentReqToPeer
 	self.mlock.Lock()
 	req, ok := self.sentReqs[msg.ReqID]
 	self.mlock.Unlock()
 	if ok {
 		req.lock.Lock()
-		delivered, ok = req.sentTo[peer]
+		chans, ok = req.sentTo[peer]
+		delete(req.sentTo, peer)
 		req.lock.Unlock()
 	}
 
@@ -114,9 +139,8 @@ func (self *LesOdr) Deliver(peer *peer, msg *Msg) error {
 	}
 
 	if req.valFunc(self.db, msg) {
-		close(delivered)
+		close(chans.delivered)
 		req.lock.Lock()
-		delete(req.sentTo, peer)
 		if req.answered != nil {
 			close(req.answered)
 			req.answered = nil
@@ -124,10 +148,12 @@ func (self *LesOdr) Deliver(peer *peer, msg *Msg) error {
 		req.lock.Unlock()
 		return nil
 	}
+	// let the request fail over to the next server without waiting for a timeout
+	close(chans.invalid)
 	return errResp(ErrInvalidResponse, "reqID = %v", msg.ReqID)
 }
 
-func (self *LesOdr) requestPeer(req *sentReq, peer *peer, delivered, timeout chan struct{}, reqWg *sync.WaitGroup) {
+func (self *LesOdr) requestPeer(req *sentReq, peer *peer, chans sentReqToPeer, timeout chan struct{}, reqWg *sync.WaitGroup) {
 	stime := mclock.Now()
 	defer func() {
 		req.lock.Lock()
@@ -137,19 +163,32 @@ func (self *LesOdr) requestPeer(req *sentReq, peer *peer, delivered, timeout cha
 	}()
 
 	select {
-	case <-delivered:
+	case <-chans.delivered:
+		req.metrics.reqTimer.Update(time.Duration(mclock.Now() - stime))
 		if self.serverPool != nil {
 			self.serverPool.adjustResponseTime(peer.poolEntry, time.Duration(mclock.Now()-stime), false)
 		}
 		return
+	case <-chans.invalid:
+		// invalid answers are scored like timeouts; the peer itself is dropped by the handler
+		req.metrics.invalidMeter.Mark(1)
+		close(timeout)
+		if self.serverPool != nil {
+			self.serverPool.adjustResponseTime(peer.poolEntry, time.Duration(mclock.Now()-stime), true)
+		}
+		return
 	case <-time.After(softRequestTimeout):
+		req.metrics.timeoutMeter.Mark(1)
 		close(timeout)
 	case <-self.stop:
 		return
 	}
 
 	select {
-	case <-delivered:
+	case <-chans.delivered:
+		req.metrics.reqTimer.Update(time.Duration(mclock.Now() - stime))
+	case <-chans.invalid:
+		req.metrics.invalidMeter.Mark(1)
 	case <-time.After(hardRequestTimeout):
 		glog.V(logger.Debug).Infof("ODR hard request timeout from peer %v", peer.id)
 		go self.removePeer(peer.id)
@@ -167,9 +206,11 @@ func (self *LesOdr) networkRequest(ctx context.Context, lreq LesOdrRequest) erro
 	answered := make(chan struct{})
 	req := &sentReq{
 		valFunc:  lreq.Valid,
-		sentTo:   make(map[*peer]chan struct{}),
+		metrics:  requestMetrics(lreq),
+		sentTo:   make(map[*peer]sentReqToPeer),
 		answered: answered, // reply delivered by any peer
 	}
+	req.metrics.reqMeter.Mark(1)
 	reqID := getNextReqID()
 	self.mlock.Lock()
 	self.sentReqs[reqID] = req
@@ -205,16 +246,22 @@ func (self *LesOdr) networkRequest(ctx context.Context, lreq LesOdrRequest) erro
 			case <-time.After(retryPeers):
 			}
 		} else {
+			if len(exclude) > 0 {
+				req.metrics.retryMeter.Mark(1)
+			}
 			exclude[p] = struct{}{}
-			delivered := make(chan struct{})
+			chans := sentReqToPeer{
+				delivered: make(chan struct{}),
+				invalid:   make(chan struct{}),
+			}
 			timeout := make(chan struct{})
 			req.lock.Lock()
-			req.sentTo[p] = delivered
+			req.sentTo[p] = chans
 			req.lock.Unlock()
 			reqWg.Add(1)
 			cost := lreq.GetCost(p)
 			p.fcServer.SendRequest(reqID, cost)
-			go self.requestPeer(req, p, delivered, timeout, reqWg)
+			go self.requestPeer(req, p, chans, timeout, reqWg)
 			lreq.Request(reqID, p)
 
 			select {
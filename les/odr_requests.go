@@ -53,6 +53,8 @@ func LesRequest(req light.OdrRequest) LesOdrRequest {
 		return (*CodeRequest)(r)
 	case *light.ChtRequest:
 		return (*ChtRequest)(r)
+	case *light.TxStatusRequest:
+		return (*TxStatusRequest)(r)
 	default:
 		return nil
 	}
@@ -351,3 +353,52 @@ func (self *ChtRequest) Valid(db ethdb.Database, msg *Msg) bool {
 	glog.V(logger.Debug).Infof("ODR: validation successful")
 	return true
 }
+
+// ODR request type for requesting transaction status, see LesOdrRequest interface
+type TxStatusRequest light.TxStatusRequest
+
+// GetCost returns the cost of the given ODR request according to the serving
+// peer's cost table (implementation of LesOdrRequest)
+func (self *TxStatusRequest) GetCost(peer *peer) uint64 {
+	return peer.GetRequestCost(txStatusCostCode, len(self.Hashes))
+}
+
+// CanSend tells if a certain peer is suitable for serving the given request
+func (self *TxStatusRequest) CanSend(peer *peer) bool {
+	return peer.ServesTxStatus()
+}
+
+// Request sends an ODR request to the LES network (implementation of LesOdrRequest)
+func (self *TxStatusRequest) Request(reqID uint64, peer *peer) error {
+	glog.V(logger.Debug).Infof("ODR: requesting status of %d transactions from peer %v", len(self.Hashes), peer.id)
+	return peer.RequestTxStatus(reqID, self.GetCost(peer), self.Hashes)
+}
+
+// Valid processes an ODR request reply message from the LES network
+// returns true and stores results in memory if the message was a valid reply
+// to the request (implementation of LesOdrRequest)
+func (self *TxStatusRequest) Valid(db ethdb.Database, msg *Msg) bool {
+	glog.V(logger.Debug).Infof("ODR: validating status of %d transactions", len(self.Hashes))
+	if msg.MsgType != MsgTxStatus {
+		glog.V(logger.Debug).Infof("ODR: invalid message type")
+		return false
+	}
+	status := msg.Obj.([]light.TxStatus)
+	if len(status) != len(self.Hashes) {
+		glog.V(logger.Debug).Infof("ODR: invalid number of entries: %d", len(status))
+		return false
+	}
+	// Inclusion can only be checked against the locally known canonical chain
+	for _, s := range status {
+		if s.Status != light.TxStatusIncluded {
+			continue
+		}
+		if hash := core.GetCanonicalHash(db, s.BlockNumber); hash != (common.Hash{}) && hash != s.BlockHash {
+			glog.V(logger.Debug).Infof("ODR: block #%d %08x is not canonical", s.BlockNumber, s.BlockHash[:4])
+			return false
+		}
+	}
+	self.Status = status
+	glog.V(logger.Debug).Infof("ODR: validation successful")
+	return true
+}
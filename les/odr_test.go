@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/light"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
+	gometrics "github.com/rcrowley/go-metrics"
 	"golang.org/x/net/context"
 )
 
@@ -201,3 +202,79 @@ func testOdr(t *testing.T, protocol int, expFail uint64, fn odrTestFn) {
 	// still expect all retrievals to pass, now data should be cached locally
 	test(5)
 }
+
+// failoverServerPool is a server pool selecting the first of its peers accepted
+// by the request, in the order given.
+type failoverServerPool struct {
+	peers []*peer
+}
+
+func (p *failoverServerPool) selectPeerWait(_ uint64, canSend func(*peer) (bool, time.Duration), _ <-chan struct{}) *peer {
+	for _, peer := range p.peers {
+		if ok, _ := canSend(peer); ok {
+			return peer
+		}
+	}
+	return nil
+}
+
+func (p *failoverServerPool) adjustResponseTime(*poolEntry, time.Duration, bool) {}
+
+// Tests that a request answered invalidly by a server is sent to the next server
+// right away instead of waiting for the soft request timeout.
+func TestOdrInvalidFailoverLes1(t *testing.T) { testOdrInvalidFailover(t, 1) }
+
+func testOdrInvalidFailover(t *testing.T, protocol int) {
+	// Assemble a light client connected to a good server and to one with a
+	// different chain, unable to answer for the good blocks
+	pm, db, _ := newTestProtocolManagerMust(t, false, 4, testChainGen)
+	bad, _, _ := newTestProtocolManagerMust(t, false, 2, nil)
+	lpm, _, odr := newTestProtocolManagerMust(t, true, 0, nil)
+	_, err1, lpeer, err2 := newTestPeerPair("good", protocol, pm, lpm)
+	_, err3, lbad, err4 := newTestPeerPair("bad", protocol, bad, lpm)
+	select {
+	case <-time.After(time.Millisecond * 100):
+	case err := <-err1:
+		t.Fatalf("good peer handshake error: %v", err)
+	case err := <-err2:
+		t.Fatalf("good peer handshake error: %v", err)
+	case err := <-err3:
+		t.Fatalf("bad peer handshake error: %v", err)
+	case err := <-err4:
+		t.Fatalf("bad peer handshake error: %v", err)
+	}
+	lpm.synchronise(lpeer)
+
+	// Make the bad server the first choice, pretending both servers have all
+	// blocks as the fetcher only tracks announced heads
+	for _, p := range []*peer{lbad, lpeer} {
+		p.lock.Lock()
+		p.hasBlock = func(common.Hash, uint64) bool { return true }
+		p.lock.Unlock()
+	}
+	odr.serverPool = &failoverServerPool{peers: []*peer{lbad, lpeer}}
+
+	// Count the invalid answers, metrics are not collected by default
+	defer func(meter gometrics.Meter) { odrBodyMetrics.invalidMeter = meter }(odrBodyMetrics.invalidMeter)
+	invalid := gometrics.NewMeter()
+	odrBodyMetrics.invalidMeter = invalid
+
+	hash := core.GetCanonicalHash(db, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), softRequestTimeout)
+	defer cancel()
+
+	start := time.Now()
+	body, err := light.GetBodyRLP(ctx, odr, hash, 1)
+	if err != nil {
+		t.Fatalf("failed to retrieve block body: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > softRequestTimeout/2 {
+		t.Errorf("failover took %v, soft request timeout is %v", elapsed, softRequestTimeout)
+	}
+	if want := core.GetBodyRLP(db, hash, 1); !bytes.Equal(body, want) {
+		t.Errorf("block body mismatch: have %x, want %x", body, want)
+	}
+	if count := invalid.Count(); count != 1 {
+		t.Errorf("invalid answer count mismatch: have %d, want 1", count)
+	}
+}
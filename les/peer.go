@@ -27,6 +27,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/les/flowcontrol"
+	"github.com/ethereum/go-ethereum/light"
 	"github.com/ethereum/go-ethereum/logger"
 	"github.com/ethereum/go-ethereum/logger/glog"
 	"github.com/ethereum/go-ethereum/p2p"
@@ -34,9 +35,10 @@ import (
 )
 
 var (
-	errClosed            = errors.New("peer set is closed")
-	errAlreadyRegistered = errors.New("peer is already registered")
-	errNotRegistered     = errors.New("peer is not registered")
+	errClosed             = errors.New("peer set is closed")
+	errAlreadyRegistered  = errors.New("peer is already registered")
+	errNotRegistered      = errors.New("peer is not registered")
+	errTxStatusNotRunning = errors.New("lestx sub-protocol not running")
 )
 
 const maxHeadInfoLen = 20
@@ -63,6 +65,9 @@ type peer struct {
 	fcServer       *flowcontrol.ServerNode // nil if the peer is client only
 	fcServerParams *flowcontrol.ServerParams
 	fcCosts        requestCostTable
+
+	capabilities map[string]struct{} // extensions advertised by the server
+	txStatusRW   p2p.MsgReadWriter   // lestx sub-protocol connection, nil if not running
 }
 
 func newPeer(version, network int, p *p2p.Peer, rw p2p.MsgReadWriter) *peer {
@@ -146,6 +151,15 @@ func (p *peer) GetRequestCost(msgcode uint64, amount int) uint64 {
 	return cost
 }
 
+// HasCapability checks if the peer advertised a given protocol extension
+func (p *peer) HasCapability(name string) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	_, ok := p.capabilities[name]
+	return ok
+}
+
 // HasBlock checks if the peer has a given block
 func (p *peer) HasBlock(hash common.Hash, number uint64) bool {
 	p.lock.RLock()
@@ -193,6 +207,36 @@ func (p *peer) SendHeaderProofs(reqID, bv uint64, proofs []ChtResp) error {
 	return sendResponse(p.rw, HeaderProofsMsg, reqID, bv, proofs)
 }
 
+// setTxStatusRW attaches the connection of the lestx sub-protocol to the peer.
+func (p *peer) setTxStatusRW(rw p2p.MsgReadWriter) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.txStatusRW = rw
+}
+
+// ServesTxStatus checks if transaction status requests can be sent to the peer,
+// i.e. it advertised the extension and the lestx sub-protocol is running.
+func (p *peer) ServesTxStatus() bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	_, ok := p.capabilities[CapTxStatus]
+	return ok && p.txStatusRW != nil
+}
+
+// SendTxStatus sends a batch of transaction status records, corresponding to the ones requested.
+func (p *peer) SendTxStatus(reqID, bv uint64, status []light.TxStatus) error {
+	p.lock.RLock()
+	rw := p.txStatusRW
+	p.lock.RUnlock()
+
+	if rw == nil {
+		return errTxStatusNotRunning
+	}
+	return sendResponse(rw, TxStatusMsg, reqID, bv, status)
+}
+
 // RequestHeadersByHash fetches a batch of blocks' headers corresponding to the
 // specified header query, based on the hash of an origin block.
 func (p *peer) RequestHeadersByHash(reqID, cost uint64, origin common.Hash, amount int, skip int, reverse bool) error {
@@ -239,6 +283,19 @@ func (p *peer) RequestHeaderProofs(reqID, cost uint64, reqs []*ChtReq) error {
 	return sendRequest(p.rw, GetHeaderProofsMsg, reqID, cost, reqs)
 }
 
+// RequestTxStatus fetches a batch of transaction status records from a remote node.
+func (p *peer) RequestTxStatus(reqID, cost uint64, txHashes []common.Hash) error {
+	glog.V(logger.Debug).Infof("%v requesting status of %v transactions", p, len(txHashes))
+	p.lock.RLock()
+	rw := p.txStatusRW
+	p.lock.RUnlock()
+
+	if rw == nil {
+		return errTxStatusNotRunning
+	}
+	return sendRequest(rw, GetTxStatusMsg, reqID, cost, txHashes)
+}
+
 func (p *peer) SendTxs(cost uint64, txs types.Transactions) error {
 	glog.V(logger.Debug).Infof("%v relaying %v txs", p, len(txs))
 	reqID := getNextReqID()
@@ -337,6 +394,9 @@ func (p *peer) Handshake(td *big.Int, head common.Hash, headNum uint64, genesis
 		list := server.fcCostStats.getCurrentList()
 		send = send.add("flowControl/MRC", list)
 		p.fcCosts = list.decode()
+		if caps := server.Capabilities(); len(caps) > 0 {
+			send = send.add("capabilities", caps)
+		}
 	}
 	recvList, err := p.sendReceiveHandshake(send)
 	if err != nil {
@@ -405,6 +465,15 @@ func (p *peer) Handshake(td *big.Int, head common.Hash, headNum uint64, genesis
 		p.fcServerParams = params
 		p.fcServer = flowcontrol.NewServerNode(params)
 		p.fcCosts = MRC.decode()
+
+		// Capabilities are optional, servers without extensions omit them
+		var caps []string
+		if recv.get("capabilities", &caps) == nil {
+			p.capabilities = make(map[string]struct{})
+			for _, c := range caps {
+				p.capabilities[c] = struct{}{}
+			}
+		}
 	}
 
 	p.headInfo = &announceData{Td: rTd, Hash: rHash, Number: rNum}
@@ -38,6 +38,20 @@ var ProtocolVersions = []uint{lpv1}
 // Number of implemented message corresponding to different protocol versions.
 var ProtocolLengths = []uint64{15}
 
+// The transaction status extension is carried by a separately named sub-protocol,
+// keeping the les protocol itself compatible with other les implementations.
+const (
+	TxStatusProtocolName    = "lestx"
+	TxStatusProtocolVersion = 1
+	TxStatusProtocolLength  = 2
+)
+
+// Capabilities advertised by servers in the "capabilities" handshake entry,
+// letting clients detect protocol extensions without probing
+const (
+	CapTxStatus = "txStatus" // serves GetTxStatus requests over the lestx sub-protocol
+)
+
 const (
 	NetworkId          = 1
 	ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
@@ -63,6 +77,16 @@ const (
 	HeaderProofsMsg    = 0x0e
 )
 
+// lestx protocol message codes
+const (
+	GetTxStatusMsg = 0x00
+	TxStatusMsg    = 0x01
+)
+
+// txStatusCostCode is the key of the GetTxStatus request cost in the flow control
+// cost table, which is otherwise indexed by les message codes.
+const txStatusCostCode = 0x100
+
 type errCode int
 
 const (
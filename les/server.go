@@ -42,6 +42,7 @@ type LesServer struct {
 	fcManager       *flowcontrol.ClientManager // nil if our node is client only
 	fcCostStats     *requestCostStats
 	defParams       *flowcontrol.ServerParams
+	capabilities    []string // extensions advertised on top of the protocol defaults
 	stopped         bool
 }
 
@@ -52,7 +53,10 @@ func NewLesServer(eth *eth.Ethereum, config *eth.Config) (*LesServer, error) {
 	}
 	pm.blockLoop()
 
-	srv := &LesServer{protocolManager: pm}
+	srv := &LesServer{
+		protocolManager: pm,
+		capabilities:    config.LightServCapabilities,
+	}
 	pm.server = srv
 
 	srv.defParams = &flowcontrol.ServerParams{
@@ -68,6 +72,11 @@ func (s *LesServer) Protocols() []p2p.Protocol {
 	return s.protocolManager.SubProtocols
 }
 
+// Capabilities returns the extensions advertised to connecting clients.
+func (s *LesServer) Capabilities() []string {
+	return append([]string{CapTxStatus}, s.capabilities...)
+}
+
 // Start starts the LES server
 func (s *LesServer) Start(srvr *p2p.Server) {
 	s.protocolManager.Start(srvr)
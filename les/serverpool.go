@@ -22,10 +22,12 @@ import (
 	"math"
 	"math/rand"
 	"net"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/mclock"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/logger"
@@ -344,6 +346,47 @@ func (pool *serverPool) selectPeerWait(reqID uint64, canSend func(*peer) (bool,
 	}
 }
 
+// ServerScore is a snapshot of the statistics collected about a known server
+type ServerScore struct {
+	ID           string                `json:"id"`
+	Connected    bool                  `json:"connected"`
+	Score        float64               `json:"score"`
+	ConnectRate  float64               `json:"connectRate"`
+	ResponseTime common.PrettyDuration `json:"responseTime"`
+	BlockDelay   common.PrettyDuration `json:"blockDelay"`
+	TimeoutRate  float64               `json:"timeoutRate"`
+}
+
+// scores returns the current statistics of all known servers, best scored first
+func (pool *serverPool) scores() []ServerScore {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	list := make([]ServerScore, 0, len(pool.entries))
+	for _, entry := range pool.entries {
+		if !entry.known {
+			continue
+		}
+		list = append(list, ServerScore{
+			ID:           entry.id.String(),
+			Connected:    entry.state == psRegistered,
+			Score:        entry.score(),
+			ConnectRate:  entry.connectStats.recentAvg(),
+			ResponseTime: common.PrettyDuration(entry.responseStats.recentAvg()),
+			BlockDelay:   common.PrettyDuration(entry.delayStats.recentAvg()),
+			TimeoutRate:  entry.timeoutStats.recentAvg(),
+		})
+	}
+	sort.Sort(serverScoresByScore(list))
+	return list
+}
+
+type serverScoresByScore []ServerScore
+
+func (s serverScoresByScore) Len() int           { return len(s) }
+func (s serverScoresByScore) Less(i, j int) bool { return s[i].Score > s[j].Score }
+func (s serverScoresByScore) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
 // eventLoop handles pool events and mutex locking for all internal functions
 func (pool *serverPool) eventLoop() {
 	lookupCnt := 0
@@ -684,7 +727,13 @@ func (e *knownEntry) Weight() int64 {
 	if e.state != psNotConnected || !e.known || e.delayedRetry {
 		return 0
 	}
-	return int64(1000000000 * e.connectStats.recentAvg() * math.Exp(-float64(e.lastConnected.fails)*failDropLn-e.responseStats.recentAvg()/float64(responseScoreTC)-e.delayStats.recentAvg()/float64(delayScoreTC)) * math.Pow((1-e.timeoutStats.recentAvg()), timeoutPow))
+	return int64(1000000000 * (*poolEntry)(e).score())
+}
+
+// score calculates the quality of a known server between 0 and 1 based on its
+// connection, response time, block delay and timeout statistics
+func (e *poolEntry) score() float64 {
+	return e.connectStats.recentAvg() * math.Exp(-float64(e.lastConnected.fails)*failDropLn-e.responseStats.recentAvg()/float64(responseScoreTC)-e.delayStats.recentAvg()/float64(delayScoreTC)) * math.Pow((1-e.timeoutStats.recentAvg()), timeoutPow)
 }
 
 // poolEntryAddress is a separate object because currently it is necessary to remember
@@ -0,0 +1,66 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+func newTestPoolEntry(id byte, respTime time.Duration, timeouts float64) *poolEntry {
+	entry := &poolEntry{
+		id:            discover.NodeID{id},
+		known:         true,
+		lastConnected: &poolEntryAddress{},
+	}
+	entry.connectStats.init(1, 1)
+	entry.delayStats.init(0, 1)
+	entry.responseStats.init(float64(respTime), 1)
+	entry.timeoutStats.init(timeouts, 1)
+	return entry
+}
+
+func TestServerPoolScores(t *testing.T) {
+	pool := &serverPool{entries: make(map[discover.NodeID]*poolEntry)}
+	for _, e := range []*poolEntry{
+		newTestPoolEntry(1, 200*time.Millisecond, 0),
+		newTestPoolEntry(2, 10*time.Millisecond, 0),
+		newTestPoolEntry(3, 10*time.Millisecond, 0.5),
+	} {
+		pool.entries[e.id] = e
+	}
+	// entries that never connected are not reported
+	pool.entries[discover.NodeID{4}] = &poolEntry{id: discover.NodeID{4}}
+
+	scores := pool.scores()
+	if len(scores) != 3 {
+		t.Fatalf("score count mismatch: have %d, want 3", len(scores))
+	}
+	want := []discover.NodeID{{2}, {1}, {3}}
+	for i, id := range want {
+		if scores[i].ID != id.String() {
+			t.Errorf("score %d: have server %s, want %s", i, scores[i].ID[:8], id.String()[:8])
+		}
+	}
+	for i := 1; i < len(scores); i++ {
+		if scores[i].Score > scores[i-1].Score {
+			t.Errorf("scores not ordered: %v > %v", scores[i].Score, scores[i-1].Score)
+		}
+	}
+}
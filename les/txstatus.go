@@ -0,0 +1,164 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/logger"
+	"github.com/ethereum/go-ethereum/logger/glog"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+const (
+	txStatusPeerWait     = 100 * time.Millisecond // Polling interval while waiting for the les handshake
+	txStatusPeerDeadline = 10 * time.Second       // Maximum time to wait for the les handshake
+)
+
+// txStatusProtocol creates the lestx sub-protocol serving and retrieving the
+// status of transactions. It runs next to the les protocol of the same remote
+// node and shares the flow control state of its les peer.
+func (pm *ProtocolManager) txStatusProtocol() p2p.Protocol {
+	return p2p.Protocol{
+		Name:    TxStatusProtocolName,
+		Version: TxStatusProtocolVersion,
+		Length:  TxStatusProtocolLength,
+		Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+			peer, err := pm.waitPeer(p.ID())
+			if err != nil {
+				return err
+			}
+			peer.setTxStatusRW(rw)
+			defer peer.setTxStatusRW(nil)
+
+			for {
+				if err := pm.handleTxStatusMsg(peer, rw); err != nil {
+					glog.V(logger.Debug).Infof("%v: lestx message handling failed: %v", peer, err)
+					return err
+				}
+			}
+		},
+	}
+}
+
+// waitPeer waits until the les handshake with the given node is completed and
+// returns the registered les peer.
+func (pm *ProtocolManager) waitPeer(id discover.NodeID) (*peer, error) {
+	key := fmt.Sprintf("%x", id[:8])
+	deadline := time.After(txStatusPeerDeadline)
+	for {
+		if p := pm.peers.Peer(key); p != nil {
+			return p, nil
+		}
+		select {
+		case <-time.After(txStatusPeerWait):
+		case <-deadline:
+			return nil, errResp(ErrNoStatusMsg, "no les handshake")
+		case <-pm.quitSync:
+			return nil, p2p.DiscQuitting
+		}
+	}
+}
+
+// handleTxStatusMsg is invoked whenever an inbound message is received on the
+// lestx sub-protocol. The remote connection is torn down upon returning any error.
+func (pm *ProtocolManager) handleTxStatusMsg(p *peer, rw p2p.MsgReadWriter) error {
+	msg, err := rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if msg.Size > ProtocolMaxMsgSize {
+		return errResp(ErrMsgTooLarge, "%v > %v", msg.Size, ProtocolMaxMsgSize)
+	}
+	defer msg.Discard()
+
+	switch msg.Code {
+	case GetTxStatusMsg:
+		if pm.server == nil || p.fcClient == nil {
+			return errResp(ErrUnexpectedResponse, "")
+		}
+		glog.V(logger.Debug).Infof("<=== GetTxStatusMsg from peer %v", p.id)
+		// Decode the retrieval message
+		var req struct {
+			ReqID  uint64
+			Hashes []common.Hash
+		}
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		reqCnt := uint64(len(req.Hashes))
+		if reqCnt > MaxTxStatus {
+			return errResp(ErrRequestRejected, "")
+		}
+		costs := p.fcCosts[txStatusCostCode]
+		cost := costs.baseCost + reqCnt*costs.reqCost
+		if cost > pm.server.defParams.BufLimit {
+			cost = pm.server.defParams.BufLimit
+		}
+		if bufValue, _ := p.fcClient.AcceptRequest(); cost > bufValue {
+			glog.V(logger.Error).Infof("Request from %v came %v too early", p.id, time.Duration((cost-bufValue)*1000000/pm.server.defParams.MinRecharge))
+			return errResp(ErrRequestRejected, "")
+		}
+		status := make([]light.TxStatus, reqCnt)
+		for i, hash := range req.Hashes {
+			status[i] = pm.txStatus(hash)
+		}
+		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + reqCnt*costs.reqCost)
+		pm.server.fcCostStats.update(txStatusCostCode, reqCnt, rcost)
+		return p.SendTxStatus(req.ReqID, bv, status)
+
+	case TxStatusMsg:
+		if pm.odr == nil || p.fcServer == nil {
+			return errResp(ErrUnexpectedResponse, "")
+		}
+		glog.V(logger.Debug).Infof("<=== TxStatusMsg from peer %v", p.id)
+		var resp struct {
+			ReqID, BV uint64
+			Status    []light.TxStatus
+		}
+		if err := msg.Decode(&resp); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		p.fcServer.GotReply(resp.ReqID, resp.BV)
+		return pm.odr.Deliver(p, &Msg{
+			MsgType: MsgTxStatus,
+			ReqID:   resp.ReqID,
+			Obj:     resp.Status,
+		})
+
+	default:
+		glog.V(logger.Debug).Infof("<=== unknown lestx message with code %d from peer %v", msg.Code, p.id)
+		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
+	}
+}
+
+// txStatus returns the status of a transaction as known by the local chain and
+// transaction pool
+func (pm *ProtocolManager) txStatus(hash common.Hash) light.TxStatus {
+	if tx, blockHash, blockNumber, index := core.GetTransaction(pm.chainDb, hash); tx != nil {
+		return light.TxStatus{Status: light.TxStatusIncluded, BlockHash: blockHash, BlockNumber: blockNumber, Index: index}
+	}
+	if pm.txpool != nil && pm.txpool.Get(hash) != nil {
+		return light.TxStatus{Status: light.TxStatusPending}
+	}
+	return light.TxStatus{Status: light.TxStatusUnknown}
+}
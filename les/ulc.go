@@ -0,0 +1,82 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package les implements the Light Ethereum Subprotocol.
+package les
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+// ulc holds the configuration of the ultra light client mode, in which new chain
+// heads are only accepted once announced by enough of a set of trusted servers,
+// and headers are downloaded from those servers without verifying their PoW.
+type ulc struct {
+	trusted    map[discover.NodeID]*discover.Node
+	minTrusted int // number of trusted servers that need to announce a head
+}
+
+// defaultULCFraction is the percentage of trusted servers that need to announce
+// a head if no fraction is configured.
+const defaultULCFraction = 75
+
+// newULC creates the ultra light client configuration from the enode URLs of the
+// trusted servers and the percentage of them required to agree on a new head.
+func newULC(servers []string, fraction int) (*ulc, error) {
+	if fraction == 0 {
+		fraction = defaultULCFraction
+	}
+	if fraction < 0 || fraction > 100 {
+		return nil, fmt.Errorf("invalid trusted server fraction %d%%", fraction)
+	}
+	trusted := make(map[discover.NodeID]*discover.Node)
+	for _, url := range servers {
+		node, err := discover.ParseNode(url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted server %s: %v", url, err)
+		}
+		trusted[node.ID] = node
+	}
+	if len(trusted) == 0 {
+		return nil, errors.New("no trusted servers specified")
+	}
+	return &ulc{
+		trusted:    trusted,
+		minTrusted: (len(trusted)*fraction + 99) / 100,
+	}, nil
+}
+
+// isTrusted returns whether the node with the given ID is a trusted server.
+func (u *ulc) isTrusted(id discover.NodeID) bool {
+	_, ok := u.trusted[id]
+	return ok
+}
+
+// ulcValidator is a header validator that checks the consensus rules of headers
+// but skips their PoW verification, relying on the trusted servers instead.
+type ulcValidator struct {
+	core.HeaderValidator
+}
+
+// ValidateHeader implements core.HeaderValidator, ignoring checkPow.
+func (v ulcValidator) ValidateHeader(header, parent *types.Header, checkPow bool) error {
+	return v.HeaderValidator.ValidateHeader(header, parent, false)
+}
@@ -0,0 +1,106 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+func testTrustedServers(n int) ([]string, []discover.NodeID) {
+	urls := make([]string, n)
+	ids := make([]discover.NodeID, n)
+	for i := range urls {
+		ids[i][0] = byte(i + 1)
+		urls[i] = fmt.Sprintf("enode://%x@127.0.0.1:%d", ids[i][:], 30303+i)
+	}
+	return urls, ids
+}
+
+func TestNewULC(t *testing.T) {
+	urls, ids := testTrustedServers(4)
+
+	tests := []struct {
+		fraction, minTrusted int
+	}{
+		{0, 3}, {1, 1}, {50, 2}, {51, 3}, {100, 4},
+	}
+	for _, tt := range tests {
+		u, err := newULC(urls, tt.fraction)
+		if err != nil {
+			t.Fatalf("fraction %d: failed to create ULC config: %v", tt.fraction, err)
+		}
+		if u.minTrusted != tt.minTrusted {
+			t.Errorf("fraction %d: min trusted mismatch: have %d, want %d", tt.fraction, u.minTrusted, tt.minTrusted)
+		}
+	}
+	u, _ := newULC(urls, 0)
+	for _, id := range ids {
+		if !u.isTrusted(id) {
+			t.Errorf("server %x not trusted", id[:4])
+		}
+	}
+	if u.isTrusted(discover.NodeID{0xff}) {
+		t.Errorf("unknown server trusted")
+	}
+	if _, err := newULC(urls, 101); err == nil {
+		t.Errorf("invalid fraction accepted")
+	}
+	if _, err := newULC(nil, 50); err == nil {
+		t.Errorf("empty trusted server list accepted")
+	}
+	if _, err := newULC([]string{"enode://invalid"}, 50); err == nil {
+		t.Errorf("invalid enode URL accepted")
+	}
+}
+
+// Tests that in ultra light client mode a head is only downloaded after enough
+// trusted servers announced it, and that untrusted announcements don't count.
+func TestULCTrustedAnnounced(t *testing.T) {
+	urls, ids := testTrustedServers(3)
+	u, _ := newULC(urls, 60)
+
+	f := &lightFetcher{
+		pm:    &ProtocolManager{ulc: u},
+		peers: make(map[*peer]*fetcherPeerInfo),
+	}
+	head := common.Hash{1}
+	announce := func(id discover.NodeID) {
+		p := &peer{Peer: p2p.NewPeer(id, "test", nil)}
+		f.peers[p] = &fetcherPeerInfo{nodeByHash: map[common.Hash]*fetcherTreeNode{head: {hash: head}}}
+	}
+	announce(discover.NodeID{0xff})
+	announce(ids[0])
+	if f.trustedAnnounced(head) {
+		t.Fatalf("head accepted with 1 of 2 required trusted announcements")
+	}
+	announce(ids[1])
+	if !f.trustedAnnounced(head) {
+		t.Fatalf("head rejected with 2 of 2 required trusted announcements")
+	}
+	if f.trustedAnnounced(common.Hash{2}) {
+		t.Fatalf("unannounced head accepted")
+	}
+	f.pm.ulc = nil
+	if !f.trustedAnnounced(common.Hash{2}) {
+		t.Fatalf("head rejected with ultra light client mode disabled")
+	}
+}
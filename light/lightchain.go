@@ -17,6 +17,9 @@
 package light
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"math/big"
 	"sync"
 	"sync/atomic"
@@ -39,6 +42,8 @@ import (
 var (
 	bodyCacheLimit  = 256
 	blockCacheLimit = 256
+	pruneInterval   = time.Hour
+	pruneBatchSize  = 1024 // Number of blocks pruned while holding the chain lock
 )
 
 // LightChain represents a canonical chain that by default only handles block
@@ -107,12 +112,12 @@ func NewLightChain(odr OdrBackend, config *params.ChainConfig, pow pow.PoW, mux
 	if bc.genesisBlock.Hash() == (common.Hash{212, 229, 103, 64, 248, 118, 174, 248, 192, 16, 184, 106, 64, 213, 245, 103, 69, 161, 24, 208, 144, 106, 52, 230, 154, 236, 140, 13, 177, 203, 143, 163}) {
 		// add trusted CHT
 		if config.DAOForkSupport {
-			WriteTrustedCht(bc.chainDb, TrustedCht{
+			bc.addBuiltinCht(TrustedCht{
 				Number: 637,
 				Root:   common.HexToHash("01e408d9b1942f05dba1a879f3eaafe34d219edaeb8223fecf1244cc023d3e23"),
 			})
 		} else {
-			WriteTrustedCht(bc.chainDb, TrustedCht{
+			bc.addBuiltinCht(TrustedCht{
 				Number: 523,
 				Root:   common.HexToHash("c035076523faf514038f619715de404a65398c51899b5dccca9c05b00bc79315"),
 			})
@@ -121,7 +126,7 @@ func NewLightChain(odr OdrBackend, config *params.ChainConfig, pow pow.PoW, mux
 	} else {
 		if bc.genesisBlock.Hash() == (common.Hash{12, 215, 134, 162, 66, 93, 22, 241, 82, 198, 88, 49, 108, 66, 62, 108, 225, 24, 30, 21, 195, 41, 88, 38, 215, 201, 144, 76, 186, 156, 227, 3}) {
 			// add trusted CHT for testnet
-			WriteTrustedCht(bc.chainDb, TrustedCht{
+			bc.addBuiltinCht(TrustedCht{
 				Number: 452,
 				Root:   common.HexToHash("511da2c88e32b14cf4a4e62f7fcbb297139faebc260a4ab5eb43cce6edcba324"),
 			})
@@ -145,6 +150,103 @@ func NewLightChain(odr OdrBackend, config *params.ChainConfig, pow pow.PoW, mux
 	return bc, nil
 }
 
+// addBuiltinCht stores a hard coded trusted CHT unless a more recent one has
+// already been configured through SetTrustedCht.
+func (self *LightChain) addBuiltinCht(cht TrustedCht) {
+	if GetTrustedCht(self.chainDb).Number < cht.Number {
+		WriteTrustedCht(self.chainDb, cht)
+	}
+}
+
+// SetTrustedCht replaces the trusted CHT used to retrieve headers which are older
+// than the local chain head, letting new installs start syncing from a recent
+// checkpoint instead of the built-in one. Checkpoints older than the currently
+// trusted CHT are rejected.
+func (self *LightChain) SetTrustedCht(cht TrustedCht) error {
+	if cht.Root == (common.Hash{}) {
+		return errors.New("empty CHT root")
+	}
+	// Block numbers covered by the CHT must fit into 64 bits
+	if cht.Number > math.MaxUint64/ChtFrequency {
+		return fmt.Errorf("CHT #%d out of range", cht.Number)
+	}
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if current := GetTrustedCht(self.chainDb); cht.Number < current.Number {
+		return fmt.Errorf("CHT #%d older than trusted CHT #%d", cht.Number, current.Number)
+	}
+	WriteTrustedCht(self.chainDb, cht)
+	glog.V(logger.Info).Infof("Trusted CHT set to #%d [%x…]", cht.Number, cht.Root[:4])
+	return nil
+}
+
+// Prune removes the locally stored headers, bodies and receipts of canonical
+// blocks which are more than retention blocks behind the chain head. Only blocks
+// covered by the trusted CHT are removed so they can be retrieved again on demand;
+// the genesis block is always kept. It returns the number of pruned blocks.
+func (self *LightChain) Prune(retention uint64) int {
+	self.chainmu.RLock()
+	head := self.hc.CurrentHeader().Number.Uint64()
+	self.chainmu.RUnlock()
+
+	if head <= retention {
+		return 0
+	}
+	limit := head - retention
+	if chtLimit := GetTrustedCht(self.chainDb).Number * ChtFrequency; chtLimit < limit {
+		limit = chtLimit
+	}
+	// Walk backwards until reaching the genesis or an already pruned block. The
+	// chain lock is only held for a batch at a time to not stall header import.
+	pruned, number := 0, limit
+	for done := false; !done && number > 1; {
+		select {
+		case <-self.quit:
+			done = true
+			continue
+		default:
+		}
+		self.chainmu.Lock()
+		for i := 0; i < pruneBatchSize && number > 1; i++ {
+			hash := core.GetCanonicalHash(self.chainDb, number-1)
+			if hash == (common.Hash{}) {
+				done = true
+				break
+			}
+			core.DeleteCanonicalHash(self.chainDb, number-1)
+			core.DeleteBlock(self.chainDb, hash, number-1)
+			pruned++
+			number--
+		}
+		self.chainmu.Unlock()
+	}
+	if pruned > 0 {
+		glog.V(logger.Info).Infof("Pruned %d blocks older than #%d", pruned, limit)
+	}
+	return pruned
+}
+
+// StartPruning runs Prune with the given retention periodically until the chain
+// is stopped.
+func (self *LightChain) StartPruning(retention uint64) {
+	self.wg.Add(1)
+	go func() {
+		defer self.wg.Done()
+
+		ticker := time.NewTicker(pruneInterval)
+		defer ticker.Stop()
+		for {
+			self.Prune(retention)
+			select {
+			case <-ticker.C:
+			case <-self.quit:
+				return
+			}
+		}
+	}()
+}
+
 func (self *LightChain) getProcInterrupt() bool {
 	return atomic.LoadInt32(&self.procInterrupt) == 1
 }
@@ -296,6 +398,14 @@ func (self *LightChain) GetBodyRLP(ctx context.Context, hash common.Hash) (rlp.R
 	return body, nil
 }
 
+// PurgeCaches drops the cached block bodies and blocks, e.g. to release memory.
+// They are reloaded from the database or the network on demand.
+func (self *LightChain) PurgeCaches() {
+	self.bodyCache.Purge()
+	self.bodyRLPCache.Purge()
+	self.blockCache.Purge()
+}
+
 // HasBlock checks if a block is fully present in the database or not, caching
 // it if present.
 func (bc *LightChain) HasBlock(hash common.Hash) bool {
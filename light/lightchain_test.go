@@ -18,6 +18,7 @@ package light
 
 import (
 	"fmt"
+	"math"
 	"math/big"
 	"runtime"
 	"testing"
@@ -402,3 +403,111 @@ func TestReorgBadHeaderHashes(t *testing.T) {
 		t.Errorf("last header hash mismatch: have: %x, want %x", ncm.CurrentHeader().Hash(), headers[2].Hash())
 	}
 }
+
+// Tests that the trusted CHT can be updated at runtime, but never rolled back,
+// neither explicitly nor by the built-in checkpoints when reopening the chain.
+func TestSetTrustedCht(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	bc := theLightChain(db, t)
+
+	cht := TrustedCht{Number: 10, Root: common.Hash{1}}
+	if err := bc.SetTrustedCht(cht); err != nil {
+		t.Fatalf("failed to set trusted CHT: %v", err)
+	}
+	if stored := GetTrustedCht(db); stored != cht {
+		t.Fatalf("trusted CHT mismatch: have %v, want %v", stored, cht)
+	}
+	if err := bc.SetTrustedCht(TrustedCht{Number: 5, Root: common.Hash{2}}); err == nil {
+		t.Errorf("older trusted CHT accepted")
+	}
+	if err := bc.SetTrustedCht(TrustedCht{Number: 20}); err == nil {
+		t.Errorf("trusted CHT with empty root accepted")
+	}
+	if err := bc.SetTrustedCht(TrustedCht{Number: math.MaxUint64/ChtFrequency + 1, Root: common.Hash{2}}); err == nil {
+		t.Errorf("out of range trusted CHT accepted")
+	}
+	bc.addBuiltinCht(TrustedCht{Number: 8, Root: common.Hash{3}})
+	if stored := GetTrustedCht(db); stored != cht {
+		t.Fatalf("trusted CHT overwritten: have %v, want %v", stored, cht)
+	}
+	builtin := TrustedCht{Number: 12, Root: common.Hash{4}}
+	bc.addBuiltinCht(builtin)
+	if stored := GetTrustedCht(db); stored != builtin {
+		t.Fatalf("newer built-in CHT not stored: have %v, want %v", stored, builtin)
+	}
+}
+
+// Tests that pruning removes old canonical blocks covered by the trusted CHT
+// while keeping recent ones and the genesis.
+func TestPrune(t *testing.T)        { testPrune(t, pruneBatchSize) }
+func TestPruneBatches(t *testing.T) { testPrune(t, 2) }
+
+func testPrune(t *testing.T, batch int) {
+	defer func(old int) { pruneBatchSize = old }(pruneBatchSize)
+	pruneBatchSize = batch
+
+	db, bc, err := newCanonical(10)
+	if err != nil {
+		t.Fatalf("failed to create canonical chain: %v", err)
+	}
+	// Without a trusted CHT nothing can be retrieved again, so nothing is pruned
+	DeleteTrustedCht(db)
+	if pruned := bc.Prune(4); pruned != 0 {
+		t.Fatalf("pruned %d blocks without trusted CHT", pruned)
+	}
+	WriteTrustedCht(db, TrustedCht{Number: 1, Root: common.Hash{1}})
+	if pruned := bc.Prune(4); pruned != 5 {
+		t.Fatalf("pruned block count mismatch: have %d, want 5", pruned)
+	}
+	for i := uint64(0); i <= 10; i++ {
+		hash := core.GetCanonicalHash(db, i)
+		if exp := i == 0 || i >= 6; exp != (hash != common.Hash{}) {
+			t.Errorf("block #%d: canonical hash present %v, want %v", i, hash != common.Hash{}, exp)
+		}
+	}
+	if pruned := bc.Prune(4); pruned != 0 {
+		t.Errorf("pruned %d blocks again", pruned)
+	}
+}
+
+// Tests that retrieving a header whose block is pruned after its canonical hash
+// was read falls back to the trusted CHT, failing cleanly if there is none
+// instead of crashing.
+func TestGetHeaderByNumberPrunedNoCht(t *testing.T) {
+	db, bc, err := newCanonical(10)
+	if err != nil {
+		t.Fatalf("failed to create canonical chain: %v", err)
+	}
+	hash := core.GetCanonicalHash(db, 5)
+	core.DeleteBlock(db, hash, 5)
+
+	DeleteTrustedCht(db)
+	if _, err := GetHeaderByNumber(NoOdr, bc.odr, 5); err != ErrNoTrustedCht {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrNoTrustedCht)
+	}
+}
+
+// Tests that purging the caches drops the cached bodies and blocks.
+func TestPurgeCaches(t *testing.T) {
+	db, bc, err := newCanonical(2)
+	if err != nil {
+		t.Fatalf("failed to create canonical chain: %v", err)
+	}
+	hash := core.GetCanonicalHash(db, 1)
+	if err := core.WriteBody(db, hash, 1, new(types.Body)); err != nil {
+		t.Fatalf("failed to write body: %v", err)
+	}
+	if _, err := bc.GetBlock(NoOdr, hash, 1); err != nil {
+		t.Fatalf("failed to retrieve block: %v", err)
+	}
+	if _, err := bc.GetBody(NoOdr, hash); err != nil {
+		t.Fatalf("failed to retrieve body: %v", err)
+	}
+	if bc.blockCache.Len() == 0 || bc.bodyCache.Len() == 0 {
+		t.Fatalf("block and body not cached")
+	}
+	bc.PurgeCaches()
+	if n := bc.blockCache.Len() + bc.bodyCache.Len() + bc.bodyRLPCache.Len(); n != 0 {
+		t.Errorf("%d items left in the caches", n)
+	}
+}
@@ -157,3 +157,29 @@ func (req *ChtRequest) StoreResult(db ethdb.Database) {
 	core.WriteCanonicalHash(db, hash, num)
 	//storeProof(db, req.Proof)
 }
+
+// Transaction status codes reported by light servers
+const (
+	TxStatusUnknown  = iota // transaction is neither pending nor included in the canonical chain
+	TxStatusPending         // transaction is in the server's transaction pool
+	TxStatusIncluded        // transaction is included in the canonical chain
+)
+
+// TxStatus describes the status of a transaction as reported by a light server.
+// The block fields are only set if the transaction has been included.
+type TxStatus struct {
+	Status      uint
+	BlockHash   common.Hash
+	BlockNumber uint64
+	Index       uint64
+}
+
+// TxStatusRequest is the ODR request type for retrieving transaction status
+type TxStatusRequest struct {
+	OdrRequest
+	Hashes []common.Hash
+	Status []TxStatus
+}
+
+// StoreResult stores the retrieved data in local database
+func (req *TxStatusRequest) StoreResult(db ethdb.Database) {}
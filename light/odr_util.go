@@ -75,12 +75,11 @@ func GetHeaderByNumber(ctx context.Context, odr OdrBackend, number uint64) (*typ
 	db := odr.Database()
 	hash := core.GetCanonicalHash(db, number)
 	if (hash != common.Hash{}) {
-		// if there is a canonical hash, there is a header too
-		header := core.GetHeader(db, hash, number)
-		if header == nil {
-			panic("Canonical hash present but header not found")
+		// if there is a canonical hash, there is a header too unless the block
+		// is being pruned concurrently, in which case retrieve it via the CHT
+		if header := core.GetHeader(db, hash, number); header != nil {
+			return header, nil
 		}
-		return header, nil
 	}
 
 	cht := GetTrustedCht(db)
@@ -185,3 +184,13 @@ func GetBlockReceipts(ctx context.Context, odr OdrBackend, hash common.Hash, num
 		return r.Receipts, nil
 	}
 }
+
+// GetTransactionStatus retrieves the status of the given transactions from the
+// network. The status is reported by the servers and is not verified locally.
+func GetTransactionStatus(ctx context.Context, odr OdrBackend, hashes []common.Hash) ([]TxStatus, error) {
+	r := &TxStatusRequest{Hashes: hashes}
+	if err := odr.Retrieve(ctx, r); err != nil {
+		return nil, err
+	}
+	return r.Status, nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/hashicorp/golang-lru"
 	"golang.org/x/net/context"
 )
 
@@ -246,3 +247,41 @@ func TestLightStateDelete(t *testing.T) {
 		t.Fatalf("HasSuicided returned false, expected true")
 	}
 }
+
+type cachingTestOdr struct {
+	*testOdr
+	cache *lru.Cache
+}
+
+func (odr *cachingTestOdr) TrieValueCache() *lru.Cache {
+	return odr.cache
+}
+
+func TestLightTrieValueCache(t *testing.T) {
+	root, sdb := makeTestState()
+	header := &types.Header{Root: root, Number: big.NewInt(0)}
+	ldb, _ := ethdb.NewMemDatabase()
+	cache, _ := lru.New(TrieValueCacheLimit)
+	odr := &cachingTestOdr{&testOdr{sdb: sdb, ldb: ldb}, cache}
+	ctx := context.Background()
+
+	addr := common.Address{1}
+	exp, err := NewLightTrie(StateTrieID(header), odr, true).Get(ctx, addr[:])
+	if err != nil || len(exp) == 0 {
+		t.Fatalf("failed to retrieve account: %x, %v", exp, err)
+	}
+	// Drop the retrieved trie nodes, the value should be served from the cache
+	odr.ldb, _ = ethdb.NewMemDatabase()
+	odr.disable = true
+	val, err := NewLightTrie(StateTrieID(header), odr, true).Get(ctx, addr[:])
+	if err != nil {
+		t.Fatalf("failed to retrieve cached account: %v", err)
+	}
+	if !bytes.Equal(val, exp) {
+		t.Fatalf("cached account mismatch: have %x, want %x", val, exp)
+	}
+	// Without the cache the value is not available any more
+	if _, err := NewLightTrie(StateTrieID(header), odr.testOdr, true).Get(ctx, addr[:]); err == nil {
+		t.Fatalf("account retrieved without cache and ODR")
+	}
+}
@@ -19,27 +19,46 @@ package light
 import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/trie"
+	"github.com/hashicorp/golang-lru"
 	"golang.org/x/net/context"
 )
 
+// TrieValueCacheLimit is the suggested number of trie entries kept in memory by
+// ODR backends implementing TrieValueCacher
+const TrieValueCacheLimit = 4096
+
+// TrieValueCacher is an optional interface of ODR backends which keep recently
+// read trie values in memory, keyed by trie root and key. Since a trie root
+// identifies its whole content, entries never become stale; the ones belonging
+// to old chain heads simply age out of the cache.
+type TrieValueCacher interface {
+	TrieValueCache() *lru.Cache
+}
+
 // LightTrie is an ODR-capable wrapper around trie.SecureTrie
 type LightTrie struct {
-	trie *trie.SecureTrie
-	id   *TrieID
-	odr  OdrBackend
-	db   ethdb.Database
+	trie  *trie.SecureTrie
+	id    *TrieID
+	odr   OdrBackend
+	db    ethdb.Database
+	cache *lru.Cache
+	dirty bool // trie has been modified, its content no longer matches id.Root
 }
 
 // NewLightTrie creates a new LightTrie instance. It doesn't instantly try to
 // access the db or network and retrieve the root node, it only initializes its
 // encapsulated SecureTrie at the first actual operation.
 func NewLightTrie(id *TrieID, odr OdrBackend, useFakeMap bool) *LightTrie {
-	return &LightTrie{
+	t := &LightTrie{
 		// SecureTrie is initialized before first request
 		id:  id,
 		odr: odr,
 		db:  odr.Database(),
 	}
+	if c, ok := odr.(TrieValueCacher); ok {
+		t.cache = c.TrieValueCache()
+	}
+	return t
 }
 
 // retrieveKey retrieves a single key, returns true and stores nodes in local
@@ -76,6 +95,13 @@ func (t *LightTrie) do(ctx context.Context, fallbackKey []byte, fn func() error)
 // Get returns the value for key stored in the trie.
 // The value bytes must not be modified by the caller.
 func (t *LightTrie) Get(ctx context.Context, key []byte) (res []byte, err error) {
+	useCache := t.cache != nil && !t.dirty
+	cacheKey := string(t.id.Root[:]) + string(key)
+	if useCache {
+		if cached, ok := t.cache.Get(cacheKey); ok {
+			return cached.([]byte), nil
+		}
+	}
 	err = t.do(ctx, key, func() (err error) {
 		if t.trie == nil {
 			t.trie, err = trie.NewSecure(t.id.Root, t.db, 0)
@@ -85,6 +111,9 @@ func (t *LightTrie) Get(ctx context.Context, key []byte) (res []byte, err error)
 		}
 		return
 	})
+	if err == nil && useCache {
+		t.cache.Add(cacheKey, res)
+	}
 	return
 }
 
@@ -100,6 +129,7 @@ func (t *LightTrie) Update(ctx context.Context, key, value []byte) (err error) {
 			t.trie, err = trie.NewSecure(t.id.Root, t.db, 0)
 		}
 		if err == nil {
+			t.dirty = true
 			err = t.trie.TryUpdate(key, value)
 		}
 		return
@@ -114,6 +144,7 @@ func (t *LightTrie) Delete(ctx context.Context, key []byte) (err error) {
 			t.trie, err = trie.NewSecure(t.id.Root, t.db, 0)
 		}
 		if err == nil {
+			t.dirty = true
 			err = t.trie.TryDelete(key)
 		}
 		return
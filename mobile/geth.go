@@ -20,9 +20,11 @@
 package geth
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"path/filepath"
+	"runtime/debug"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/eth"
@@ -30,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethstats"
 	"github.com/ethereum/go-ethereum/les"
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p/discover"
 	"github.com/ethereum/go-ethereum/p2p/nat"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/whisper/whisperv2"
@@ -62,6 +65,23 @@ type NodeConfig struct {
 	// empty genesis state is equivalent to using the mainnet's state.
 	EthereumGenesis string
 
+	// EthereumCheckpointNumber and EthereumCheckpointRoot specify a trusted CHT to
+	// start light client header sync from instead of the built-in one. A nil root
+	// uses the built-in checkpoint.
+	EthereumCheckpointNumber int64
+	EthereumCheckpointRoot   *Hash
+
+	// UltraLightServers are trusted light servers whose head announcements
+	// are accepted without verifying the proof-of-work, and UltraLightFraction
+	// the percentage of them that must announce a new head (0 meaning 75%). The
+	// servers are dialed directly, so their IP address and port must be known.
+	UltraLightServers  *Enodes
+	UltraLightFraction int
+
+	// EthereumPruneRetention is the number of recent blocks whose data is kept when
+	// periodically pruning the light chain database. Zero disables pruning.
+	EthereumPruneRetention int64
+
 	// EthereumDatabaseCache is the system memory in MB to allocate for database caching.
 	// A minimum of 16MB is always reserved.
 	EthereumDatabaseCache int
@@ -74,6 +94,65 @@ type NodeConfig struct {
 
 	// WhisperEnabled specifies whether the node should run the Whisper protocol.
 	WhisperEnabled bool
+
+	// KeyStoreScryptN and KeyStoreScryptP are the scrypt parameters used to encrypt
+	// keys in the node's key store. Zero values select the standard parameters, which
+	// may take several seconds per unlock on low-end devices (see LightScryptN).
+	KeyStoreScryptN int
+	KeyStoreScryptP int
+
+	// MemoryProfile tunes the memory hungry settings (database, trie and Whisper
+	// message caches, peer count) to the capabilities of the device. Use one of the
+	// MemoryProfile constants.
+	MemoryProfile int
+}
+
+// Memory profiles selectable in NodeConfig.
+const (
+	MemoryProfileNormal = 0 // Use the configured values as is
+	MemoryProfileLow    = 1 // Minimal caches and fewer peers for low-end devices
+	MemoryProfileHigh   = 2 // Larger caches for devices with plenty of memory
+)
+
+// Number of trie values cached by the light client in the low and high memory
+// profiles (the normal profile uses light.TrieValueCacheLimit), and number of
+// Whisper messages tracked in the low profile (unlimited otherwise).
+const (
+	lowMemoryTrieCache    = 512
+	highMemoryTrieCache   = 16384
+	lowMemoryWhisperLimit = 256
+)
+
+// memoryLimits are the cache sizes selected by a memory profile, zero meaning
+// the package defaults.
+type memoryLimits struct {
+	trieCache       int // Number of trie values cached by the light client
+	whisperMessages int // Number of Whisper messages tracked by the node
+}
+
+// applyMemoryProfile adjusts the node configuration according to the selected
+// memory profile. The low profile caps the peer count at 10, uses the minimum
+// database cache and shrinks the light client's trie value cache and the Whisper
+// message pool. The high one raises the database and trie caches. It returns the
+// cache sizes to use for the services.
+func applyMemoryProfile(config *NodeConfig) (memoryLimits, error) {
+	switch config.MemoryProfile {
+	case MemoryProfileNormal:
+		return memoryLimits{}, nil
+	case MemoryProfileLow:
+		if config.MaxPeers > 10 {
+			config.MaxPeers = 10
+		}
+		config.EthereumDatabaseCache = 16
+		return memoryLimits{trieCache: lowMemoryTrieCache, whisperMessages: lowMemoryWhisperLimit}, nil
+	case MemoryProfileHigh:
+		if config.EthereumDatabaseCache < 128 {
+			config.EthereumDatabaseCache = 128
+		}
+		return memoryLimits{trieCache: highMemoryTrieCache}, nil
+	default:
+		return memoryLimits{}, fmt.Errorf("unknown memory profile %d", config.MemoryProfile)
+	}
 }
 
 // defaultNodeConfig contains the default node configuration values to use if all
@@ -110,6 +189,16 @@ func NewNode(datadir string, config *NodeConfig) (stack *Node, _ error) {
 	if config.BootstrapNodes == nil || config.BootstrapNodes.Size() == 0 {
 		config.BootstrapNodes = defaultNodeConfig.BootstrapNodes
 	}
+	limits, err := applyMemoryProfile(config)
+	if err != nil {
+		return nil, err
+	}
+	if config.EthereumCheckpointNumber < 0 {
+		return nil, fmt.Errorf("negative checkpoint number %d", config.EthereumCheckpointNumber)
+	}
+	if config.EthereumPruneRetention < 0 {
+		return nil, fmt.Errorf("negative prune retention %d", config.EthereumPruneRetention)
+	}
 	// Create the empty networking stack
 	nodeConf := &node.Config{
 		Name:             clientIdentifier,
@@ -123,6 +212,8 @@ func NewNode(datadir string, config *NodeConfig) (stack *Node, _ error) {
 		ListenAddr:       ":0",
 		NAT:              nat.Any(),
 		MaxPeers:         config.MaxPeers,
+		KeyStoreScryptN:  config.KeyStoreScryptN,
+		KeyStoreScryptP:  config.KeyStoreScryptP,
 	}
 	rawStack, err := node.New(nodeConf)
 	if err != nil {
@@ -144,6 +235,9 @@ func NewNode(datadir string, config *NodeConfig) (stack *Node, _ error) {
 			Genesis:                 config.EthereumGenesis,
 			LightMode:               true,
 			DatabaseCache:           config.EthereumDatabaseCache,
+			LightCheckpointNumber:   uint64(config.EthereumCheckpointNumber),
+			LightTrieCache:          limits.trieCache,
+			LightPruneRetention:     uint64(config.EthereumPruneRetention),
 			NetworkId:               config.EthereumNetworkID,
 			GasPrice:                new(big.Int).Mul(big.NewInt(20), common.Shannon),
 			GpoMinGasPrice:          new(big.Int).Mul(big.NewInt(20), common.Shannon),
@@ -153,6 +247,15 @@ func NewNode(datadir string, config *NodeConfig) (stack *Node, _ error) {
 			GpobaseStepUp:           100,
 			GpobaseCorrectionFactor: 110,
 		}
+		if config.EthereumCheckpointRoot != nil {
+			ethConf.LightCheckpointRoot = config.EthereumCheckpointRoot.hash
+		}
+		if config.UltraLightServers != nil {
+			if ethConf.UltraLightServers, err = ultraLightServers(config.UltraLightServers); err != nil {
+				return nil, err
+			}
+			ethConf.UltraLightFraction = config.UltraLightFraction
+		}
 		if err := rawStack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
 			return les.New(ctx, ethConf)
 		}); err != nil {
@@ -172,13 +275,28 @@ func NewNode(datadir string, config *NodeConfig) (stack *Node, _ error) {
 	}
 	// Register the Whisper protocol if requested
 	if config.WhisperEnabled {
-		if err := rawStack.Register(func(*node.ServiceContext) (node.Service, error) { return whisperv2.New(), nil }); err != nil {
+		if err := rawStack.Register(func(*node.ServiceContext) (node.Service, error) {
+			shh := whisperv2.New()
+			shh.SetMaxMessages(limits.whisperMessages)
+			return shh, nil
+		}); err != nil {
 			return nil, fmt.Errorf("whisper init: %v", err)
 		}
 	}
 	return &Node{rawStack}, nil
 }
 
+// ReleaseMemory drops the light client's block and trie caches, then forces a
+// garbage collection and returns as much memory to the operating system as
+// possible. Call it on memory pressure warnings of the host.
+func (n *Node) ReleaseMemory() {
+	var lesServ *les.LightEthereum
+	if err := n.node.Service(&lesServ); err == nil {
+		lesServ.PurgeCaches()
+	}
+	debug.FreeOSMemory()
+}
+
 // Start creates a live P2P node and starts running it.
 func (n *Node) Start() error {
 	return n.node.Start()
@@ -208,3 +326,61 @@ func (n *Node) GetNodeInfo() *NodeInfo {
 func (n *Node) GetPeersInfo() *PeerInfos {
 	return &PeerInfos{n.node.Server().PeersInfo()}
 }
+
+// staticPeer converts an enode into a dialable p2p node. Incomplete enodes are
+// rejected as the mobile node runs without discovery to resolve them.
+func staticPeer(peer *Enode) (*discover.Node, error) {
+	if peer == nil {
+		return nil, errors.New("nil enode")
+	}
+	if peer.node.Incomplete() {
+		return nil, errors.New("incomplete enode, IP address and port required")
+	}
+	return discover.NewNode(discover.NodeID(peer.node.ID), peer.node.IP, peer.node.UDP, peer.node.TCP), nil
+}
+
+// ultraLightServers converts the trusted light servers of the ultra light client
+// mode into enode URLs, rejecting the ones that can't be dialed directly.
+func ultraLightServers(enodes *Enodes) ([]string, error) {
+	urls := make([]string, 0, len(enodes.nodes))
+	for i, enode := range enodes.nodes {
+		if enode == nil {
+			return nil, fmt.Errorf("ultra light server %d: nil enode", i)
+		}
+		p, err := staticPeer(&Enode{enode})
+		if err != nil {
+			return nil, fmt.Errorf("ultra light server %d: %v", i, err)
+		}
+		urls = append(urls, p.String())
+	}
+	return urls, nil
+}
+
+// AddPeer adds a static peer (e.g. a trusted light server) to the running node,
+// which will keep the connection alive until the peer is removed.
+func (n *Node) AddPeer(peer *Enode) error {
+	p, err := staticPeer(peer)
+	if err != nil {
+		return err
+	}
+	server := n.node.Server()
+	if server == nil {
+		return node.ErrNodeStopped
+	}
+	server.AddPeer(p)
+	return nil
+}
+
+// RemovePeer disconnects from a previously added static peer.
+func (n *Node) RemovePeer(peer *Enode) error {
+	p, err := staticPeer(peer)
+	if err != nil {
+		return err
+	}
+	server := n.node.Server()
+	if server == nil {
+		return node.ErrNodeStopped
+	}
+	server.RemovePeer(p)
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import "testing"
+
+// Tests that memory profiles tune the node configuration as documented.
+func TestApplyMemoryProfile(t *testing.T) {
+	tests := []struct {
+		profile int
+		peers   int
+		cache   int
+		limits  memoryLimits
+		fail    bool
+	}{
+		{profile: MemoryProfileNormal, peers: 25, cache: 16},
+		{profile: MemoryProfileLow, peers: 10, cache: 16, limits: memoryLimits{trieCache: lowMemoryTrieCache, whisperMessages: lowMemoryWhisperLimit}},
+		{profile: MemoryProfileHigh, peers: 25, cache: 128, limits: memoryLimits{trieCache: highMemoryTrieCache}},
+		{profile: 3, fail: true},
+	}
+	for i, tt := range tests {
+		config := NewNodeConfig()
+		config.MemoryProfile = tt.profile
+
+		limits, err := applyMemoryProfile(config)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("test %d: expected error for profile %d", i, tt.profile)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+			continue
+		}
+		if config.MaxPeers != tt.peers || config.EthereumDatabaseCache != tt.cache || limits != tt.limits {
+			t.Errorf("test %d: config mismatch: have peers %d, cache %d, limits %+v; want %d, %d, %+v",
+				i, config.MaxPeers, config.EthereumDatabaseCache, limits, tt.peers, tt.cache, tt.limits)
+		}
+	}
+	// Explicitly configured larger caches are kept by the high profile
+	config := NewNodeConfig()
+	config.MemoryProfile, config.EthereumDatabaseCache = MemoryProfileHigh, 256
+	if _, err := applyMemoryProfile(config); err != nil || config.EthereumDatabaseCache != 256 {
+		t.Errorf("high profile lowered database cache to %d (err %v)", config.EthereumDatabaseCache, err)
+	}
+}
+
+// Tests that static peers are rejected unless they can be dialed directly.
+func TestStaticPeerValidation(t *testing.T) {
+	const id = "1dd9d65c4552b5eb43d5ad55a2ee3f56c6cbc1c64a5c8d659f51fcd51bace24351232b8d7821617d2b29b54b81cdefb9b3e9c37d7fd5f63270bcc9e1a6f6a439"
+
+	incomplete, err := NewEnode("enode://" + id)
+	if err != nil {
+		t.Fatalf("failed to parse incomplete enode: %v", err)
+	}
+	complete, err := NewEnode("enode://" + id + "@10.3.58.6:30303")
+	if err != nil {
+		t.Fatalf("failed to parse complete enode: %v", err)
+	}
+	if _, err := staticPeer(nil); err == nil {
+		t.Errorf("nil enode accepted")
+	}
+	if _, err := staticPeer(incomplete); err == nil {
+		t.Errorf("incomplete enode accepted")
+	}
+	if _, err := staticPeer(complete); err != nil {
+		t.Errorf("complete enode rejected: %v", err)
+	}
+	// Invalid peers are rejected before the node is touched
+	if err := new(Node).AddPeer(nil); err == nil {
+		t.Errorf("nil enode added")
+	}
+	if err := new(Node).RemovePeer(incomplete); err == nil {
+		t.Errorf("incomplete enode removed")
+	}
+}
+
+// Tests that ultra light servers are converted to dialable enode URLs.
+func TestUltraLightServers(t *testing.T) {
+	const url = "enode://1dd9d65c4552b5eb43d5ad55a2ee3f56c6cbc1c64a5c8d659f51fcd51bace24351232b8d7821617d2b29b54b81cdefb9b3e9c37d7fd5f63270bcc9e1a6f6a439@10.3.58.6:30303"
+
+	enode, err := NewEnode(url)
+	if err != nil {
+		t.Fatalf("failed to parse enode: %v", err)
+	}
+	enodes := NewEnodesEmpty()
+	enodes.Append(enode)
+
+	urls, err := ultraLightServers(enodes)
+	if err != nil {
+		t.Fatalf("failed to convert servers: %v", err)
+	}
+	if len(urls) != 1 || urls[0] != url {
+		t.Errorf("server URL mismatch: have %v, want [%s]", urls, url)
+	}
+	// Unset and incomplete servers are rejected
+	if _, err := ultraLightServers(NewEnodes(1)); err == nil {
+		t.Errorf("unset server accepted")
+	}
+	incomplete, err := NewEnode(url[:len("enode://")+128])
+	if err != nil {
+		t.Fatalf("failed to parse incomplete enode: %v", err)
+	}
+	enodes.Append(incomplete)
+	if _, err := ultraLightServers(enodes); err == nil {
+		t.Errorf("incomplete server accepted")
+	}
+}
+
+// Tests that negative numbers are rejected instead of wrapping around when
+// converted to unsigned configuration values.
+func TestNewNodeNegativeNumbers(t *testing.T) {
+	config := NewNodeConfig()
+	config.EthereumCheckpointNumber = -1
+	if _, err := NewNode("", config); err == nil {
+		t.Errorf("negative checkpoint number accepted")
+	}
+	config = NewNodeConfig()
+	config.EthereumPruneRetention = -1
+	if _, err := NewNode("", config); err == nil {
+		t.Errorf("negative prune retention accepted")
+	}
+}
@@ -81,6 +81,13 @@ type Config struct {
 	// scrypt KDF at the expense of security.
 	UseLightweightKDF bool
 
+	// KeyStoreScryptN and KeyStoreScryptP, if non-zero, override the scrypt KDF
+	// parameters selected by UseLightweightKDF for newly encrypted keys. N must be
+	// a power of two. Existing key files carry their own parameters and remain
+	// readable.
+	KeyStoreScryptN int
+	KeyStoreScryptP int
+
 	// IPCPath is the requested location to place the IPC endpoint. If the path is
 	// a simple file name, it is placed inside the data directory (or on the root
 	// pipe path on Windows), whereas if it's a resolvable path name (absolute or
@@ -407,6 +414,19 @@ func makeAccountManager(conf *Config) (am *accounts.Manager, ephemeralKeystore s
 		scryptN = accounts.LightScryptN
 		scryptP = accounts.LightScryptP
 	}
+	if n := conf.KeyStoreScryptN; n != 0 {
+		if n <= 1 || n&(n-1) != 0 {
+			return nil, "", fmt.Errorf("invalid key store scrypt N %d: must be a power of two greater than 1", n)
+		}
+		scryptN = n
+	}
+	if p := conf.KeyStoreScryptP; p != 0 {
+		// scrypt requires r*p < 2^30, with r fixed to 8 by the key store
+		if p < 0 || p >= 1<<27 {
+			return nil, "", fmt.Errorf("invalid key store scrypt P %d: must be positive and below 2^27", p)
+		}
+		scryptP = p
+	}
 
 	var keydir string
 	switch {
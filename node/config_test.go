@@ -147,3 +147,36 @@ func TestNodeKeyPersistency(t *testing.T) {
 		t.Fatalf("ephemeral node key persisted to disk")
 	}
 }
+
+// Tests that invalid key store scrypt parameters are rejected, while zero values
+// select the defaults.
+func TestKeyStoreScryptValidation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("failed to create temporary data directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		n, p int
+		fail bool
+	}{
+		{n: 0, p: 0},
+		{n: 1 << 12, p: 6},
+		{n: 2, p: 1},
+		{n: 1, fail: true},
+		{n: -4, fail: true},
+		{n: 3000, fail: true},
+		{p: -1, fail: true},
+		{p: 1 << 27, fail: true},
+	}
+	for i, tt := range tests {
+		_, _, err := makeAccountManager(&Config{DataDir: dir, KeyStoreScryptN: tt.n, KeyStoreScryptP: tt.p})
+		if tt.fail && err == nil {
+			t.Errorf("test %d: invalid scrypt parameters N=%d, P=%d accepted", i, tt.n, tt.p)
+		}
+		if !tt.fail && err != nil {
+			t.Errorf("test %d: valid scrypt parameters N=%d, P=%d rejected: %v", i, tt.n, tt.p, err)
+		}
+	}
+}
@@ -19,11 +19,14 @@ package mailserver
 import (
 	"bytes"
 	"encoding/binary"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/logger"
 	"github.com/ethereum/go-ethereum/logger/glog"
+	"github.com/ethereum/go-ethereum/p2p/discover"
 	"github.com/ethereum/go-ethereum/rlp"
 	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -32,11 +35,36 @@ import (
 
 const MailServerKeyName = "958e04ab302fb36ad2616a352cbac79d"
 
+const (
+	// pruneInterval is the time between two removals of expired archive entries
+	// and the minimum time between two evictions of stale rate limiting records
+	pruneInterval = time.Hour
+
+	// pruneBatchSize is the number of archive entries removed in one database write
+	pruneBatchSize = 1024
+)
+
+// Config holds the optional restrictions of a mail server
+type Config struct {
+	RateLimit    time.Duration     // Minimum time between two requests of the same peer (0 = unlimited)
+	AllowedPeers []discover.NodeID // Peers allowed to request mail (empty = any peer knowing the password)
+	Retention    time.Duration     // Age after which archived envelopes are removed (0 = keep forever)
+}
+
 type WMailServer struct {
 	db  *leveldb.DB
 	w   *whisper.Whisper
 	pow float64
 	key []byte
+
+	config    Config
+	allowed   map[discover.NodeID]struct{}
+	lastReq   map[discover.NodeID]time.Time // last request time of each peer, for rate limiting
+	lastEvict time.Time
+	lock      sync.Mutex
+
+	quit chan struct{} // Stops the archive pruning loop, nil if not running
+	wg   sync.WaitGroup
 }
 
 type DBKey struct {
@@ -81,7 +109,33 @@ func (s *WMailServer) Init(shh *whisper.Whisper, path string, password string, p
 	s.key = s.w.GetSymKey(MailServerKeyName)
 }
 
+// Configure sets the optional restrictions of the mail server and starts pruning
+// the archive if a retention is set. It must be called after Init and before the
+// whisper protocol is started.
+func (s *WMailServer) Configure(config Config) {
+	s.config = config
+	s.allowed = nil
+	if len(config.AllowedPeers) > 0 {
+		s.allowed = make(map[discover.NodeID]struct{})
+		for _, id := range config.AllowedPeers {
+			s.allowed[id] = struct{}{}
+		}
+	}
+	s.lastReq = make(map[discover.NodeID]time.Time)
+
+	if config.Retention > 0 && s.quit == nil {
+		s.quit = make(chan struct{})
+		s.wg.Add(1)
+		go s.pruneLoop()
+	}
+}
+
 func (s *WMailServer) Close() {
+	if s.quit != nil {
+		close(s.quit)
+		s.wg.Wait()
+		s.quit = nil
+	}
 	if s.db != nil {
 		s.db.Close()
 	}
@@ -100,6 +154,66 @@ func (s *WMailServer) Archive(env *whisper.Envelope) {
 	}
 }
 
+// pruneLoop periodically removes the archived envelopes older than the configured
+// retention until the mail server is closed.
+func (s *WMailServer) pruneLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		s.prune(uint32(time.Now().Add(-s.config.Retention).Unix()))
+		select {
+		case <-ticker.C:
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// prune removes the archived envelopes sent before the given time, returning the
+// number of removed envelopes.
+func (s *WMailServer) prune(before uint32) int {
+	var zero common.Hash
+	i := s.db.NewIterator(&util.Range{Limit: NewDbKey(before, zero).raw}, nil)
+	defer i.Release()
+
+	// Delete in bounded batches, checking for termination in between
+	var pruned int
+	batch := new(leveldb.Batch)
+	flush := func() bool {
+		if err := s.db.Write(batch, nil); err != nil {
+			glog.V(logger.Error).Infof("Pruning DB failed: %s", err)
+			return false
+		}
+		pruned += batch.Len()
+		batch.Reset()
+		return true
+	}
+	for i.Next() {
+		batch.Delete(i.Key())
+		if batch.Len() < pruneBatchSize {
+			continue
+		}
+		if !flush() {
+			return pruned
+		}
+		select {
+		case <-s.quit:
+			return pruned
+		default:
+		}
+	}
+	if err := i.Error(); err != nil {
+		glog.V(logger.Error).Infof("Level DB iterator error: %s", err)
+		return pruned
+	}
+	flush()
+	glog.V(logger.Debug).Infof("Pruned %d envelopes from the archive", pruned)
+	return pruned
+}
+
 func (s *WMailServer) DeliverMail(peer *whisper.Peer, request *whisper.Envelope) {
 	ok, lower, upper, topic := s.validate(peer, request)
 	if !ok {
@@ -138,6 +252,11 @@ func (s *WMailServer) DeliverMail(peer *whisper.Peer, request *whisper.Envelope)
 
 func (s *WMailServer) validate(peer *whisper.Peer, request *whisper.Envelope) (bool, uint32, uint32, whisper.TopicType) {
 	var topic whisper.TopicType
+	var id discover.NodeID
+	copy(id[:], peer.ID())
+	if !s.accept(id, time.Now()) {
+		return false, 0, 0, topic
+	}
 	if s.pow > 0.0 && request.PoW() < s.pow {
 		return false, 0, 0, topic
 	}
@@ -168,3 +287,33 @@ func (s *WMailServer) validate(peer *whisper.Peer, request *whisper.Envelope) (b
 
 	return true, lower, upper, topic
 }
+
+// accept checks whether the peer is allowed to request mail at the given time.
+func (s *WMailServer) accept(id discover.NodeID, now time.Time) bool {
+	if s.allowed != nil {
+		if _, ok := s.allowed[id]; !ok {
+			glog.V(logger.Warn).Infof("p2p request from unauthorized peer %x", id[:8])
+			return false
+		}
+	}
+	if s.config.RateLimit > 0 {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		// Drop the records of peers which are no longer limited
+		if now.Sub(s.lastEvict) > pruneInterval {
+			for peer, last := range s.lastReq {
+				if now.Sub(last) >= s.config.RateLimit {
+					delete(s.lastReq, peer)
+				}
+			}
+			s.lastEvict = now
+		}
+		if last, ok := s.lastReq[id]; ok && now.Sub(last) < s.config.RateLimit {
+			glog.V(logger.Warn).Infof("p2p request from peer %x exceeds rate limit", id[:8])
+			return false
+		}
+		s.lastReq[id] = now
+	}
+	return true
+}
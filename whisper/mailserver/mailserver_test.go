@@ -0,0 +1,158 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package mailserver
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+func TestAcceptAllowlist(t *testing.T) {
+	allowed, other := discover.NodeID{1}, discover.NodeID{2}
+
+	var s WMailServer
+	s.Configure(Config{})
+	if !s.accept(other, time.Now()) {
+		t.Fatalf("peer rejected without allowlist")
+	}
+	s.Configure(Config{AllowedPeers: []discover.NodeID{allowed}})
+	if !s.accept(allowed, time.Now()) {
+		t.Errorf("allowed peer rejected")
+	}
+	if s.accept(other, time.Now()) {
+		t.Errorf("unauthorized peer accepted")
+	}
+}
+
+func TestAcceptRateLimit(t *testing.T) {
+	peer1, peer2 := discover.NodeID{1}, discover.NodeID{2}
+
+	var s WMailServer
+	s.Configure(Config{RateLimit: time.Minute})
+
+	now := time.Now()
+	if !s.accept(peer1, now) {
+		t.Fatalf("first request rejected")
+	}
+	if s.accept(peer1, now.Add(30*time.Second)) {
+		t.Errorf("request within rate limit window accepted")
+	}
+	if !s.accept(peer2, now.Add(30*time.Second)) {
+		t.Errorf("request of other peer rejected")
+	}
+	if !s.accept(peer1, now.Add(time.Minute)) {
+		t.Errorf("request after rate limit window rejected")
+	}
+	// Records of peers outside the window are dropped at most once per interval
+	if len(s.lastReq) != 2 {
+		t.Fatalf("rate limit records mismatch: have %d, want 2", len(s.lastReq))
+	}
+	if !s.accept(peer2, now.Add(2*pruneInterval)) {
+		t.Errorf("request after eviction rejected")
+	}
+	if len(s.lastReq) != 1 {
+		t.Errorf("stale rate limit records not evicted: have %d, want 1", len(s.lastReq))
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mailserver-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var s WMailServer
+	if s.db, err = leveldb.OpenFile(dir, nil); err != nil {
+		t.Fatalf("failed to open DB: %v", err)
+	}
+	defer s.Close()
+
+	for _, ts := range []uint32{100, 199, 200, 300} {
+		if err := s.db.Put(NewDbKey(ts, common.Hash{byte(ts)}).raw, []byte{1}, nil); err != nil {
+			t.Fatalf("failed to write envelope: %v", err)
+		}
+	}
+	if pruned := s.prune(200); pruned != 2 {
+		t.Errorf("pruned envelope count mismatch: have %d, want 2", pruned)
+	}
+	for _, ts := range []uint32{100, 199, 200, 300} {
+		has, _ := s.db.Has(NewDbKey(ts, common.Hash{byte(ts)}).raw, nil)
+		if has != (ts >= 200) {
+			t.Errorf("envelope at %d: present %v, want %v", ts, has, ts >= 200)
+		}
+	}
+	if pruned := s.prune(200); pruned != 0 {
+		t.Errorf("pruned %d envelopes again", pruned)
+	}
+}
+
+// Tests that archives larger than a single batch are pruned completely, and that
+// a configured retention prunes the archive in the background until closed.
+func TestPruneLoop(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mailserver-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var s WMailServer
+	if s.db, err = leveldb.OpenFile(dir, nil); err != nil {
+		t.Fatalf("failed to open DB: %v", err)
+	}
+	defer s.Close()
+
+	count := pruneBatchSize*2 + pruneBatchSize/2
+	for i := 0; i < count; i++ {
+		if err := s.db.Put(NewDbKey(uint32(i), common.Hash{}).raw, []byte{1}, nil); err != nil {
+			t.Fatalf("failed to write envelope: %v", err)
+		}
+	}
+	recent := NewDbKey(uint32(time.Now().Unix()), common.Hash{}).raw
+	if err := s.db.Put(recent, []byte{1}, nil); err != nil {
+		t.Fatalf("failed to write envelope: %v", err)
+	}
+	s.Configure(Config{Retention: time.Hour})
+
+	for i := 0; ; i++ {
+		if has, _ := s.db.Has(NewDbKey(uint32(count-1), common.Hash{}).raw, nil); !has {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("archive not pruned in the background")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for i := 0; i < count; i++ {
+		if has, _ := s.db.Has(NewDbKey(uint32(i), common.Hash{}).raw, nil); has {
+			t.Fatalf("envelope at %d not pruned", i)
+		}
+	}
+	if has, _ := s.db.Has(recent, nil); !has {
+		t.Errorf("recent envelope pruned")
+	}
+	s.Close()
+	if s.quit != nil {
+		t.Errorf("pruning loop not stopped")
+	}
+}
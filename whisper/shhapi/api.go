@@ -17,6 +17,7 @@
 package shhapi
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -89,16 +90,59 @@ func (api *PublicWhisperAPI) MarkPeerTrusted(peerID hexutil.Bytes) error {
 	return api.whisper.MarkPeerTrusted(peerID)
 }
 
-// RequestHistoricMessages requests the peer to deliver the old (expired) messages.
-// data contains parameters (time frame, payment details, etc.), required
-// by the remote email-like server. Whisper is not aware about the data format,
-// it will just forward the raw data to the server.
-//func (api *PublicWhisperAPI) RequestHistoricMessages(peerID hexutil.Bytes, data hexutil.Bytes) error {
-//	if api.whisper == nil {
-//		return whisperOffLineErr
-//	}
-//	return api.whisper.RequestHistoricMessages(peerID, data)
-//}
+// RequestHistoricMessages asks a mail server peer to deliver the archived
+// (possibly expired) envelopes of the given time frame and topic. The request
+// is encrypted with the symmetric key shared with the server. The delivered
+// envelopes are passed to the filters accepting p2p messages.
+func (api *PublicWhisperAPI) RequestHistoricMessages(args HistoricMessagesArgs) error {
+	if api.whisper == nil {
+		return whisperOffLineErr
+	}
+	envelope, err := api.makeHistoricMessagesRequest(args)
+	if err != nil {
+		glog.V(logger.Error).Infoln(err)
+		return err
+	}
+	return api.whisper.RequestHistoricMessages(args.PeerID, envelope)
+}
+
+// makeHistoricMessagesRequest creates the envelope requesting historic messages
+// from a mail server. Its payload holds the lower and upper limit of the time
+// frame followed by the optional topic.
+func (api *PublicWhisperAPI) makeHistoricMessagesRequest(args HistoricMessagesArgs) (*whisperv5.Envelope, error) {
+	key := api.whisper.GetSymKey(args.KeyName)
+	if len(key) == 0 {
+		return nil, errors.New("RequestHistoricMessages: key was not found by name: " + args.KeyName)
+	}
+	upper := args.Upper
+	if upper == 0 {
+		upper = 0xFFFFFFFF
+	}
+	if args.Lower > upper {
+		return nil, errors.New("RequestHistoricMessages: invalid time frame")
+	}
+	data := make([]byte, 8, 8+whisperv5.TopicLength)
+	binary.BigEndian.PutUint32(data, args.Lower)
+	binary.BigEndian.PutUint32(data[4:], upper)
+	if (args.Topic != whisperv5.TopicType{}) {
+		data = append(data, args.Topic[:]...)
+	}
+
+	params := whisperv5.MessageParams{
+		TTL:      whisperv5.DefaultTTL,
+		KeySym:   key,
+		Payload:  data,
+		WorkTime: args.WorkTime,
+		PoW:      args.PoW,
+	}
+	if len(args.From) > 0 {
+		params.Src = api.whisper.GetIdentity(args.From)
+		if params.Src == nil {
+			return nil, errors.New("RequestHistoricMessages: non-existent identity provided")
+		}
+	}
+	return whisperv5.NewSentMessage(&params).Wrap(&params)
+}
 
 // HasIdentity checks if the whisper node is configured with the private key
 // of the specified public pair.
@@ -393,6 +437,17 @@ type PostArgs struct {
 	PeerID   hexutil.Bytes       `json:"peerID"`
 }
 
+type HistoricMessagesArgs struct {
+	PeerID   hexutil.Bytes       `json:"peerID"`  // mail server to request the messages from
+	Lower    uint32              `json:"lower"`   // lower limit of the time frame (unix timestamp)
+	Upper    uint32              `json:"upper"`   // upper limit of the time frame, zero means no limit
+	Topic    whisperv5.TopicType `json:"topic"`   // empty topic requests all messages of the time frame
+	KeyName  string              `json:"keyname"` // symmetric key shared with the mail server
+	From     string              `json:"from"`    // optional identity signing the request
+	WorkTime uint32              `json:"worktime"`
+	PoW      float64             `json:"pow"`
+}
+
 type WhisperFilterArgs struct {
 	To        string
 	From      string
@@ -569,3 +569,62 @@ func TestIntegrationSymWithFilter(t *testing.T) {
 		t.Fatalf("failed to decrypt second message: %s.", text)
 	}
 }
+
+func TestHistoricMessagesRequest(t *testing.T) {
+	api := NewPublicWhisperAPI()
+	if api == nil {
+		t.Fatalf("failed to create API.")
+	}
+
+	keyname := "mailserver"
+	args := HistoricMessagesArgs{
+		Lower:    100,
+		Upper:    200,
+		Topic:    whisperv5.TopicType{0xf2, 0x6e, 0x77, 0x79},
+		KeyName:  keyname,
+		WorkTime: 2,
+		PoW:      whisperv5.MinimumPoW,
+	}
+	if _, err := api.makeHistoricMessagesRequest(args); err == nil {
+		t.Fatalf("request created without symmetric key.")
+	}
+	if err := api.GenerateSymKey(keyname); err != nil {
+		t.Fatalf("failed GenerateSymKey: %s.", err)
+	}
+
+	envelope, err := api.makeHistoricMessagesRequest(args)
+	if err != nil {
+		t.Fatalf("failed to create request: %s.", err)
+	}
+	msg := envelope.Open(&whisperv5.Filter{KeySym: api.whisper.GetSymKey(keyname)})
+	if msg == nil {
+		t.Fatalf("failed to decrypt request.")
+	}
+	exp := append([]byte{0, 0, 0, 100, 0, 0, 0, 200}, args.Topic[:]...)
+	if !bytes.Equal(msg.Payload, exp) {
+		t.Fatalf("request payload mismatch: have %x, want %x.", msg.Payload, exp)
+	}
+
+	args.Lower, args.Upper = 300, 200
+	if _, err := api.makeHistoricMessagesRequest(args); err == nil {
+		t.Fatalf("request created with invalid time frame.")
+	}
+}
+
+func TestUnmarshalHistoricMessagesArgs(t *testing.T) {
+	s := []byte(`{"peerID":"0x01","lower":100,"upper":200,"keyname":"mailserver","from":"0x04ee"}`)
+	var args HistoricMessagesArgs
+	if err := json.Unmarshal(s, &args); err != nil {
+		t.Fatalf("failed UnmarshalJSON: %s.", err)
+	}
+	if args.Lower != 100 || args.Upper != 200 {
+		t.Fatalf("wrong time frame: %d-%d.", args.Lower, args.Upper)
+	}
+	if args.From != "0x04ee" {
+		t.Fatalf("wrong From: %s.", args.From)
+	}
+	if args.KeyName != "mailserver" || !bytes.Equal(args.PeerID, []byte{1}) {
+		t.Fatalf("wrong KeyName or PeerID: %s, %x.", args.KeyName, args.PeerID)
+	}
+}
+
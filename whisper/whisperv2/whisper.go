@@ -68,6 +68,7 @@ type Whisper struct {
 
 	messages    map[common.Hash]*Envelope // Pool of messages currently tracked by this node
 	expirations map[uint32]*set.SetNonTS  // Message expiration pool (TODO: something lighter)
+	maxMessages int                       // Maximum number of messages in the pool (0 = unlimited)
 	poolMu      sync.RWMutex              // Mutex to sync the message and expiration pools
 
 	peers  map[*peer]struct{} // Set of currently active peers
@@ -100,6 +101,16 @@ func New() *Whisper {
 	return whisper
 }
 
+// SetMaxMessages limits the number of messages tracked by the node. Messages
+// arriving while the pool is full are dropped until older ones expire. Zero
+// removes the limit.
+func (self *Whisper) SetMaxMessages(limit int) {
+	self.poolMu.Lock()
+	defer self.poolMu.Unlock()
+
+	self.maxMessages = limit
+}
+
 // APIs returns the RPC descriptors the Whisper implementation offers
 func (s *Whisper) APIs() []rpc.API {
 	return []rpc.API{
@@ -265,6 +276,10 @@ func (self *Whisper) add(envelope *Envelope) error {
 		glog.V(logger.Detail).Infof("whisper envelope already cached: %x\n", envelope)
 		return nil
 	}
+	if self.maxMessages > 0 && len(self.messages) >= self.maxMessages {
+		glog.V(logger.Detail).Infof("whisper pool full, dropping envelope %x\n", envelope)
+		return nil
+	}
 	self.messages[hash] = envelope
 
 	// Insert the message into the expiration pool for later removal
@@ -214,3 +214,33 @@ func TestMessageExpiration(t *testing.T) {
 		t.Fatalf("message was added to cache")
 	}
 }
+
+func TestMessagePoolLimit(t *testing.T) {
+	// Start the single node cluster and limit its pool to a single message
+	node := startTestCluster(1)[0]
+	node.SetMaxMessages(1)
+
+	envelopes := make([]*Envelope, 2)
+	for i := range envelopes {
+		envelope, err := NewMessage([]byte{byte(i)}).Wrap(0, Options{TTL: DefaultTTL})
+		if err != nil {
+			t.Fatalf("failed to wrap message %d: %v", i, err)
+		}
+		if err := node.Send(envelope); err != nil {
+			t.Fatalf("failed to inject message %d: %v", i, err)
+		}
+		envelopes[i] = envelope
+	}
+	// Only the first message should be tracked
+	node.poolMu.RLock()
+	_, first := node.messages[envelopes[0].Hash()]
+	_, second := node.messages[envelopes[1].Hash()]
+	node.poolMu.RUnlock()
+
+	if !first {
+		t.Fatalf("first message not found in cache")
+	}
+	if second {
+		t.Fatalf("message added to full cache")
+	}
+}